@@ -1,10 +1,7 @@
 package model
 
 import (
-	"context"
-	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 )
@@ -25,31 +22,32 @@ func (o *Operator) All(q interface{}) *Operator {
 		return o
 	}
 
+	meta, err := metaOf(q)
+	if err != nil {
+		o.Err = newOperationError("All", q, err)
+		return o
+	}
+
 	cond := expression.Key("Type").Equal(expression.Value(name))
 	softDeleteCond := expression.Not(expression.Name("DeletedAt").AttributeExists())
 	sofDeleteCond2 := expression.Not(expression.Name("DeletedAt").NotEqual(expression.Value(nil)))
-	expr, err := expression.NewBuilder().WithKeyCondition(cond).WithFilter(softDeleteCond.Or(sofDeleteCond2)).Build()
+	expr, err := withProjection(expression.NewBuilder().WithKeyCondition(cond).WithFilter(softDeleteCond.Or(sofDeleteCond2)), meta.fields, o.projection).Build()
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during All operations: %v", err)
+		o.Err = newOperationError("All", q, err)
 		return o
 	}
 
-	response, err := svc.Query(context.TODO(), &dynamodb.QueryInput{
-		TableName:                 aws.String(dynamoDBTableName),
+	err = o.queryInto(&dynamodb.QueryInput{
+		TableName:                 aws.String(o.tableName),
 		ExpressionAttributeNames:  expr.Names(),
 		ExpressionAttributeValues: expr.Values(),
 		KeyConditionExpression:    expr.KeyCondition(),
 		FilterExpression:          expr.Filter(),
-	})
-
-	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during All operations: %v", err)
-		return o
-	}
+		ProjectionExpression:      expr.Projection(),
+	}, q)
 
-	err = attributevalue.UnmarshalListOfMaps(response.Items, q)
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during All operations: %v", err)
+		o.Err = newOperationError("All", q, err)
 		return o
 	}
 
@@ -0,0 +1,323 @@
+package model
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"strings"
+)
+
+// mutation is an atomic change of a single attribute. apply changes the attribute in place, and
+// initialize replaces it when it holds NULL, which is how nil slices and pointers are stored and
+// which ADD, DELETE and list_append cannot operate on
+type mutation struct {
+	apply      func(name expression.NameBuilder) expression.UpdateBuilder
+	initialize func(name expression.NameBuilder) expression.UpdateBuilder
+}
+
+// Increment atomically adds by to the number field k of the item q points to, e.g.
+// mm.Increment(&dog, "Visits", 1). by may be negative, and a missing attribute counts as 0
+func (o *Operator) Increment(q interface{}, k string, by interface{}) *Operator {
+	return o.mutate("Increment", q, k, func(field reflect.StructField) (*mutation, error) {
+		t := indirectType(field.Type)
+		if !isNumberKind(t.Kind()) {
+			return nil, validationErrorf("field %s of type %s is not a number", k, field.Type)
+		}
+		value, err := convertValue("field "+k, t, by)
+		if err != nil {
+			return nil, err
+		}
+		return &mutation{
+			apply: func(name expression.NameBuilder) expression.UpdateBuilder {
+				return expression.Add(name, expression.Value(value.Interface()))
+			},
+			initialize: func(name expression.NameBuilder) expression.UpdateBuilder {
+				return expression.Set(name, expression.Value(value.Interface()))
+			},
+		}, nil
+	})
+}
+
+// AddToSet atomically adds values to the set field k of the item q points to, e.g.
+// mm.AddToSet(&dog, "Tags", "friendly"). The field must be stored as a set by tagging it
+// dynamodbav:",stringset", dynamodbav:",numberset" or dynamodbav:",binaryset"
+func (o *Operator) AddToSet(q interface{}, k string, values ...interface{}) *Operator {
+	return o.mutate("AddToSet", q, k, func(field reflect.StructField) (*mutation, error) {
+		set, err := setValue(field, values)
+		if err != nil {
+			return nil, err
+		}
+		return &mutation{
+			apply: func(name expression.NameBuilder) expression.UpdateBuilder {
+				return expression.Add(name, expression.Value(set))
+			},
+			initialize: func(name expression.NameBuilder) expression.UpdateBuilder {
+				return expression.Set(name, expression.Value(set))
+			},
+		}, nil
+	})
+}
+
+// RemoveFromSet atomically removes values from the set field k of the item q points to
+// DynamoDB removes the attribute once the set is empty
+func (o *Operator) RemoveFromSet(q interface{}, k string, values ...interface{}) *Operator {
+	return o.mutate("RemoveFromSet", q, k, func(field reflect.StructField) (*mutation, error) {
+		set, err := setValue(field, values)
+		if err != nil {
+			return nil, err
+		}
+		return &mutation{
+			apply: func(name expression.NameBuilder) expression.UpdateBuilder {
+				return expression.Delete(name, expression.Value(set))
+			},
+			initialize: func(name expression.NameBuilder) expression.UpdateBuilder {
+				return expression.Remove(name)
+			},
+		}, nil
+	})
+}
+
+// AppendToList atomically appends values to the end of the list field k of the item q points to,
+// e.g. mm.AppendToList(&dog, "Notes", "vaccinated")
+func (o *Operator) AppendToList(q interface{}, k string, values ...interface{}) *Operator {
+	return o.mutate("AppendToList", q, k, func(field reflect.StructField) (*mutation, error) {
+		if field.Type.Kind() != reflect.Slice || field.Type.Elem().Kind() == reflect.Uint8 || setKind(field) != "" {
+			return nil, validationErrorf("field %s of type %s is not a list", k, field.Type)
+		}
+		if len(values) == 0 {
+			return nil, validationErrorf("no values to append to field %s", k)
+		}
+		list := reflect.MakeSlice(field.Type, 0, len(values))
+		for _, v := range values {
+			value, err := convertValue("an element of field "+k, field.Type.Elem(), v)
+			if err != nil {
+				return nil, err
+			}
+			list = reflect.Append(list, value)
+		}
+		empty := &types.AttributeValueMemberL{Value: []types.AttributeValue{}}
+		return &mutation{
+			apply: func(name expression.NameBuilder) expression.UpdateBuilder {
+				return expression.Set(name, expression.ListAppend(expression.IfNotExists(name, expression.Value(empty)), expression.Value(list.Interface())))
+			},
+			initialize: func(name expression.NameBuilder) expression.UpdateBuilder {
+				return expression.Set(name, expression.Value(list.Interface()))
+			},
+		}, nil
+	})
+}
+
+// RemoveAttribute atomically removes the attribute of field k from the item q points to,
+// leaving the field zero-valued
+func (o *Operator) RemoveAttribute(q interface{}, k string) *Operator {
+	return o.mutate("RemoveAttribute", q, k, func(field reflect.StructField) (*mutation, error) {
+		remove := func(name expression.NameBuilder) expression.UpdateBuilder {
+			return expression.Remove(name)
+		}
+		return &mutation{apply: remove, initialize: remove}, nil
+	})
+}
+
+// mutate validates q and its field k, builds the mutation of the attribute with plan and applies it
+// in a single UpdateItem that also sets UpdatedAt. q is then refreshed with the item as stored
+// Versioned models have their version incremented without a check, so the change cannot be
+// undone by a concurrent Save of a stale copy. Validation rules and hooks are not run, as the
+// resulting value is only known once DynamoDB applied the change
+func (o *Operator) mutate(operation string, q interface{}, k string, plan func(reflect.StructField) (*mutation, error)) *Operator {
+	if o.Err != nil {
+		return o
+	}
+
+	name, err := ParseModelName(q)
+	if err != nil {
+		o.Err = err
+		return o
+	}
+	err = ValidateInput(q, operation, name)
+	if err != nil {
+		o.Err = err
+		return o
+	}
+
+	payload := reflect.ValueOf(q).Elem()
+	meta, err := metaFor(payload.Type())
+	if err != nil {
+		o.Err = newOperationError(operation, q, err)
+		return o
+	}
+	field, err := updatableField(payload, meta, k)
+	if err != nil {
+		o.Err = newOperationError(operation, q, err)
+		return o
+	}
+	m, err := plan(field)
+	if err != nil {
+		o.Err = newOperationError(operation, q, err)
+		return o
+	}
+
+	// The mutation assumes the attribute is missing or holds a value it can change. When it holds NULL
+	// instead the attribute is initialized, and in case another writer initialized it in between the
+	// mutation is tried once more
+	attribute := expression.NameNoDotSplit(meta.fields.attribute(k))
+	exists := expression.AttributeExists(expression.Name("ID"))
+	attempts := []struct {
+		update    expression.UpdateBuilder
+		condition expression.ConditionBuilder
+	}{
+		{m.apply(attribute), exists.And(expression.Not(attribute.AttributeType(expression.Null)))},
+		{m.initialize(attribute), exists.And(attribute.AttributeType(expression.Null))},
+		{m.apply(attribute), exists.And(expression.Not(attribute.AttributeType(expression.Null)))},
+	}
+
+	var out *dynamodb.UpdateItemOutput
+	for _, attempt := range attempts {
+		update := attempt.update.Set(expression.Name("UpdatedAt"), expression.Value(timestampValue(timestamp())))
+		if meta.versionAttribute != "" {
+			update = update.Add(expression.NameNoDotSplit(meta.versionAttribute), expression.Value(1))
+		}
+		expr, err := expression.NewBuilder().WithCondition(attempt.condition).WithUpdate(update).Build()
+		if err != nil {
+			o.Err = newOperationError(operation, q, err)
+			return o
+		}
+
+		out, err = o.db.UpdateItem(o.Context(), &dynamodb.UpdateItemInput{
+			TableName:                 aws.String(o.tableName),
+			Key:                       modelKey(payload),
+			ConditionExpression:       expr.Condition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			UpdateExpression:          expr.Update(),
+			ReturnValues:              types.ReturnValueAllNew,
+		})
+		if err == nil {
+			break
+		}
+		if !isConditionFailed(err) {
+			o.Err = newOperationError(operation, q, err)
+			return o
+		}
+		out = nil
+	}
+	if out == nil {
+		// Every attempt failed its condition, which in practice means the item does not exist
+		o.Err = newOperationError(operation, q, ErrNotFound)
+		return o
+	}
+
+	err = refreshModel(payload, out.Attributes)
+	if err != nil {
+		o.Err = newOperationError(operation, q, err)
+		return o
+	}
+	o.track(q)
+	return o
+}
+
+// setKind returns the kind of set the field is stored as, such as "stringset", or "" if it is not a set
+func setKind(field reflect.StructField) string {
+	options := strings.Split(field.Tag.Get("dynamodbav"), ",")
+	for _, option := range options[1:] {
+		switch option {
+		case "stringset", "numberset", "binaryset":
+			return option
+		}
+	}
+	return ""
+}
+
+// setValue builds the set attribute holding values, converted to the element type of the set field
+// Duplicates are dropped since DynamoDB rejects sets that contain them
+func setValue(field reflect.StructField, values []interface{}) (types.AttributeValue, error) {
+	kind := setKind(field)
+	if kind == "" || field.Type.Kind() != reflect.Slice {
+		return nil, validationErrorf("field %s of type %s is not a set, tag it dynamodbav:\",stringset\", \",numberset\" or \",binaryset\"", field.Name, field.Type)
+	}
+	if len(values) == 0 {
+		return nil, validationErrorf("no values for set field %s", field.Name)
+	}
+
+	var elements [][]byte
+	seen := map[string]bool{}
+	for _, v := range values {
+		value, err := convertValue("an element of field "+field.Name, field.Type.Elem(), v)
+		if err != nil {
+			return nil, err
+		}
+		marshalled, err := attributevalue.Marshal(value.Interface())
+		if err != nil {
+			return nil, err
+		}
+
+		var element []byte
+		switch marshalled := marshalled.(type) {
+		case *types.AttributeValueMemberS:
+			element = []byte(marshalled.Value)
+			err = expectSetKind(field, kind, "stringset")
+		case *types.AttributeValueMemberN:
+			element = []byte(marshalled.Value)
+			err = expectSetKind(field, kind, "numberset")
+		case *types.AttributeValueMemberB:
+			element = marshalled.Value
+			err = expectSetKind(field, kind, "binaryset")
+		default:
+			err = expectSetKind(field, kind, "")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !seen[string(element)] {
+			seen[string(element)] = true
+			elements = append(elements, element)
+		}
+	}
+
+	switch kind {
+	case "stringset":
+		return &types.AttributeValueMemberSS{Value: setStrings(elements)}, nil
+	case "numberset":
+		return &types.AttributeValueMemberNS{Value: setStrings(elements)}, nil
+	}
+	return &types.AttributeValueMemberBS{Value: elements}, nil
+}
+
+// expectSetKind checks that the elements of the set field, which marshal into a want, fit its kind
+func expectSetKind(field reflect.StructField, kind, want string) error {
+	if kind != want {
+		return validationErrorf("field %s of type %s cannot be stored as a %s", field.Name, field.Type, kind)
+	}
+	return nil
+}
+
+func setStrings(elements [][]byte) []string {
+	strs := make([]string, len(elements))
+	for i, element := range elements {
+		strs[i] = string(element)
+	}
+	return strs
+}
+
+// refreshModel replaces the stored fields of the model payload holds with item, as returned by
+// ReturnValues ALL_NEW. Fields that are not stored, such as those tagged dynamodbav:"-", are kept
+func refreshModel(payload reflect.Value, item map[string]types.AttributeValue) error {
+	fresh := reflect.New(payload.Type()).Elem()
+	fresh.Set(payload)
+	for i := 0; i < fresh.NumField(); i++ {
+		field := fresh.Type().Field(i)
+		if field.IsExported() && strings.Split(field.Tag.Get("dynamodbav"), ",")[0] != "-" {
+			fresh.Field(i).Set(reflect.Zero(field.Type))
+		}
+	}
+
+	err := attributevalue.UnmarshalMap(item, fresh.Addr().Interface())
+	if err != nil {
+		return err
+	}
+	payload.Set(fresh)
+	return nil
+}
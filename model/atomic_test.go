@@ -0,0 +1,191 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type TestCountedDog struct {
+	Model
+	Name    string
+	Visits  int
+	Tags    []string `dynamodbav:",stringset"`
+	Notes   []string
+	Version int64  `mm:"version"`
+	Scratch string `dynamodbav:"-"`
+}
+
+// updateValues returns the values an update expression sets, adds or deletes by attribute name
+func updateValues(in *dynamodb.UpdateItemInput) map[string]types.AttributeValue {
+	values := map[string]types.AttributeValue{}
+	expr := aws.ToString(in.UpdateExpression)
+	for namePlaceholder, name := range in.ExpressionAttributeNames {
+		for valuePlaceholder, value := range in.ExpressionAttributeValues {
+			if strings.Contains(expr, namePlaceholder+" = "+valuePlaceholder) || strings.Contains(expr, namePlaceholder+" "+valuePlaceholder) {
+				values[name] = value
+			}
+		}
+	}
+	return values
+}
+
+func TestOperator_AtomicMutations(t *testing.T) {
+	stored := TestCountedDog{
+		Model:   Model{ID: "1", Type: "test_counted_dog"},
+		Name:    "Buddy",
+		Visits:  8,
+		Tags:    []string{"good", "friendly"},
+		Notes:   []string{"fed", "vaccinated"},
+		Version: 3,
+	}
+	conditionFailed := &types.ConditionalCheckFailedException{Message: aws.String("conditional check failed")}
+
+	tests := []struct {
+		name      string
+		mutate    func(*Operator, *TestCountedDog) *Operator
+		setupMock func(*mocks.DynamoDBAPI)
+		expectErr string
+	}{
+		{
+			name:   "increment",
+			mutate: func(op *Operator, d *TestCountedDog) *Operator { return op.Increment(d, "Visits", int64(1)) },
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					expr := aws.ToString(in.UpdateExpression)
+					return strings.Contains(expr, "ADD") && updateValues(in)["Visits"].(*types.AttributeValueMemberN).Value == "1" &&
+						in.ReturnValues == types.ReturnValueAllNew
+				}), mock.Anything).Return(&dynamodb.UpdateItemOutput{Attributes: storedItem(t, stored)}, nil).Once()
+			},
+		},
+		{
+			name: "add_to_set_drops_duplicates",
+			mutate: func(op *Operator, d *TestCountedDog) *Operator {
+				return op.AddToSet(d, "Tags", "friendly", "good", "friendly")
+			},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					set, ok := updateValues(in)["Tags"].(*types.AttributeValueMemberSS)
+					return ok && len(set.Value) == 2 && strings.HasPrefix(aws.ToString(in.UpdateExpression), "ADD")
+				}), mock.Anything).Return(&dynamodb.UpdateItemOutput{Attributes: storedItem(t, stored)}, nil).Once()
+			},
+		},
+		{
+			name:   "remove_from_set",
+			mutate: func(op *Operator, d *TestCountedDog) *Operator { return op.RemoveFromSet(d, "Tags", "bad") },
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return strings.Contains(aws.ToString(in.UpdateExpression), "DELETE")
+				}), mock.Anything).Return(&dynamodb.UpdateItemOutput{Attributes: storedItem(t, stored)}, nil).Once()
+			},
+		},
+		{
+			name:   "append_to_list",
+			mutate: func(op *Operator, d *TestCountedDog) *Operator { return op.AppendToList(d, "Notes", "vaccinated") },
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					expr := aws.ToString(in.UpdateExpression)
+					return strings.Contains(expr, "list_append") && strings.Contains(expr, "if_not_exists")
+				}), mock.Anything).Return(&dynamodb.UpdateItemOutput{Attributes: storedItem(t, stored)}, nil).Once()
+			},
+		},
+		{
+			name: "append_to_null_list_initializes_it",
+			mutate: func(op *Operator, d *TestCountedDog) *Operator {
+				return op.AppendToList(d, "Notes", "fed", "vaccinated")
+			},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return strings.Contains(aws.ToString(in.UpdateExpression), "list_append")
+				}), mock.Anything).Return(nil, conditionFailed).Once()
+				dbMock.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					list, ok := updateValues(in)["Notes"].(*types.AttributeValueMemberL)
+					return ok && len(list.Value) == 2 && !strings.Contains(aws.ToString(in.ConditionExpression), "NOT")
+				}), mock.Anything).Return(&dynamodb.UpdateItemOutput{Attributes: storedItem(t, stored)}, nil).Once()
+			},
+		},
+		{
+			name:   "remove_attribute",
+			mutate: func(op *Operator, d *TestCountedDog) *Operator { return op.RemoveAttribute(d, "Name") },
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return updatedNames(in)["Name"] && strings.Contains(aws.ToString(in.UpdateExpression), "REMOVE")
+				}), mock.Anything).Return(&dynamodb.UpdateItemOutput{Attributes: storedItem(t, stored)}, nil).Once()
+			},
+		},
+		{
+			name:   "missing_item",
+			mutate: func(op *Operator, d *TestCountedDog) *Operator { return op.Increment(d, "Visits", 1) },
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.Anything, mock.Anything).Return(nil, conditionFailed).Times(3)
+			},
+			expectErr: "encountered an error during Increment operation: item not found",
+		},
+		{
+			name:      "increment_non_number",
+			mutate:    func(op *Operator, d *TestCountedDog) *Operator { return op.Increment(d, "Name", 1) },
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			expectErr: "field Name of type string is not a number",
+		},
+		{
+			name:      "increment_lossy_amount",
+			mutate:    func(op *Operator, d *TestCountedDog) *Operator { return op.Increment(d, "Visits", 0.5) },
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			expectErr: "value 0.5 does not fit field Visits of type int",
+		},
+		{
+			name:      "increment_version",
+			mutate:    func(op *Operator, d *TestCountedDog) *Operator { return op.Increment(d, "Version", 1) },
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			expectErr: "field Version is managed by magicmodel",
+		},
+		{
+			name:      "add_to_list",
+			mutate:    func(op *Operator, d *TestCountedDog) *Operator { return op.AddToSet(d, "Notes", "fed") },
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			expectErr: "field Notes of type []string is not a set",
+		},
+		{
+			name:      "append_to_set",
+			mutate:    func(op *Operator, d *TestCountedDog) *Operator { return op.AppendToList(d, "Tags", "good") },
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			expectErr: "field Tags of type []string is not a list",
+		},
+		{
+			name:      "mismatched_element",
+			mutate:    func(op *Operator, d *TestCountedDog) *Operator { return op.AddToSet(d, "Tags", 4) },
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			expectErr: "cannot set an element of field Tags of type string to a int",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			tc.setupMock(mockDB)
+
+			dog := &TestCountedDog{Model: Model{ID: "1", Type: "test_counted_dog"}, Name: "Buddy", Visits: 7, Version: 2, Scratch: "kept"}
+			err := tc.mutate(NewMagicModelOperatorWithClient(mockDB, "test-table"), dog).Err
+
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				require.Equal(t, 7, dog.Visits)
+				return
+			}
+
+			// The struct is refreshed with the stored item and keeps its fields that are not stored
+			require.NoError(t, err)
+			require.Equal(t, stored.Visits, dog.Visits)
+			require.Equal(t, stored.Tags, dog.Tags)
+			require.Equal(t, stored.Notes, dog.Notes)
+			require.Equal(t, stored.Version, dog.Version)
+			require.Equal(t, "kept", dog.Scratch)
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxBatchWriteItems is the most requests DynamoDB accepts in a single BatchWriteItem call
+const maxBatchWriteItems = 25
+
+// batchMaxRetries is how many times unprocessed items are resent before they are reported as failed
+var batchMaxRetries = 5
+
+// batchRetryDelay is the initial backoff before resending unprocessed items, doubled on every retry
+var batchRetryDelay = 50 * time.Millisecond
+
+// errUnprocessed is reported for items DynamoDB still had not processed after every retry
+var errUnprocessed = mark(errors.New("item was not processed by DynamoDB after retrying"), ErrThrottled)
+
+// BatchItemError describes a single item that could not be written by a batch operation
+// Index is the item's position in the slice passed to the batch method
+type BatchItemError struct {
+	Index int
+	ID    string
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("item %d (%s): %v", e.Index, e.ID, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError is returned by CreateMany, SaveMany and DeleteMany when some items finally failed
+// Every other item was written successfully
+type BatchError struct {
+	Operation string
+	Items     []*BatchItemError
+}
+
+func (e *BatchError) Error() string {
+	reasons := make([]string, len(e.Items))
+	for i, item := range e.Items {
+		reasons[i] = item.Error()
+	}
+	return fmt.Sprintf("encountered an error during %s operation: %d items failed: %s", e.Operation, len(e.Items), strings.Join(reasons, "; "))
+}
+
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Items))
+	for i, item := range e.Items {
+		errs[i] = item
+	}
+	return errs
+}
+
+// batchRequest is a write request along with the index of the slice element it came from
+type batchRequest struct {
+	index   int
+	id      string
+	request types.WriteRequest
+	write   *preparedWrite
+}
+
+// CreateMany creates every item in the slice q points to using BatchWriteItem
+// IDs and timestamps are assigned as in Create. Unlike Create, the writes are not conditional,
+// so a batch cannot detect items that already exist
+func (o *Operator) CreateMany(q interface{}) *Operator {
+	return o.batchWrite(q, "CreateMany", func(item interface{}) (*preparedWrite, types.WriteRequest, error) {
+		w, err := prepareCreate(o.Context(), item)
+		if err != nil {
+			return nil, types.WriteRequest{}, err
+		}
+		return w, types.WriteRequest{PutRequest: &types.PutRequest{Item: w.item}}, nil
+	})
+}
+
+// SaveMany saves every item in the slice q points to using BatchWriteItem, assigning IDs and
+// timestamps to new items as in Save. BatchWriteItem cannot check versions, so versioned
+// models must be saved with Save or Transaction instead
+func (o *Operator) SaveMany(q interface{}) *Operator {
+	return o.batchWrite(q, "SaveMany", func(item interface{}) (*preparedWrite, types.WriteRequest, error) {
+		w, err := prepareSave(o.Context(), item)
+		if err != nil {
+			return nil, types.WriteRequest{}, err
+		}
+		if w.conflict != nil {
+			return nil, types.WriteRequest{}, validationErrorf("versioned models cannot be saved in a batch, use Save or Transaction instead")
+		}
+		return w, types.WriteRequest{PutRequest: &types.PutRequest{Item: w.item}}, nil
+	})
+}
+
+// DeleteMany deletes every item in the slice q points to using BatchWriteItem
+func (o *Operator) DeleteMany(q interface{}) *Operator {
+	return o.batchWrite(q, "DeleteMany", func(item interface{}) (*preparedWrite, types.WriteRequest, error) {
+		w, err := prepareDelete(o.Context(), item)
+		if err != nil {
+			return nil, types.WriteRequest{}, err
+		}
+		return w, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: w.key}}, nil
+	})
+}
+
+// batchWrite builds a write request for every element of the slice q points to and sends them
+// in chunks, collecting the items that could not be written into a BatchError
+func (o *Operator) batchWrite(q interface{}, operation string, build func(item interface{}) (*preparedWrite, types.WriteRequest, error)) *Operator {
+	if o.Err != nil {
+		return o
+	}
+
+	name, err := ParseModelName(q)
+	if err != nil {
+		o.Err = err
+		return o
+	}
+
+	err = validateInputSlice(q, operation, name)
+	if err != nil {
+		o.Err = err
+		return o
+	}
+
+	batchErr := &BatchError{Operation: operation}
+	var requests []batchRequest
+
+	slice := reflect.ValueOf(q).Elem()
+	for i := 0; i < slice.Len(); i++ {
+		elem := slice.Index(i)
+		if elem.Kind() != reflect.Ptr {
+			elem = elem.Addr()
+		}
+		if elem.IsNil() {
+			batchErr.Items = append(batchErr.Items, &BatchItemError{Index: i, Err: validationErrorf("item is nil")})
+			continue
+		}
+
+		w, request, err := build(elem.Interface())
+		if err != nil {
+			batchErr.Items = append(batchErr.Items, &BatchItemError{Index: i, ID: elem.Elem().FieldByName("ID").String(), Err: err})
+			continue
+		}
+		requests = append(requests, batchRequest{index: i, id: elem.Elem().FieldByName("ID").String(), request: request, write: w})
+	}
+
+	for start := 0; start < len(requests); start += maxBatchWriteItems {
+		end := min(start+maxBatchWriteItems, len(requests))
+		batchErr.Items = append(batchErr.Items, o.writeChunk(requests[start:end])...)
+	}
+
+	failed := make(map[int]bool, len(batchErr.Items))
+	for _, item := range batchErr.Items {
+		failed[item.Index] = true
+	}
+	for _, r := range requests {
+		if failed[r.index] {
+			continue
+		}
+		r.write.refresh(o.snapshots)
+		if err := r.write.succeeded(o.Context()); err != nil {
+			batchErr.Items = append(batchErr.Items, &BatchItemError{Index: r.index, ID: r.id, Err: err})
+		}
+	}
+
+	if len(batchErr.Items) > 0 {
+		sort.Slice(batchErr.Items, func(i, j int) bool {
+			return batchErr.Items[i].Index < batchErr.Items[j].Index
+		})
+		o.Err = batchErr
+	}
+	return o
+}
+
+// writeChunk sends a single BatchWriteItem call and resends unprocessed items with exponential backoff
+func (o *Operator) writeChunk(chunk []batchRequest) []*BatchItemError {
+	byID := make(map[string]batchRequest, len(chunk))
+	pending := make([]types.WriteRequest, len(chunk))
+	for i, r := range chunk {
+		byID[r.id] = r
+		pending[i] = r.request
+	}
+
+	delay := batchRetryDelay
+	for attempt := 0; ; attempt++ {
+		out, err := o.db.BatchWriteItem(o.Context(), &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{o.tableName: pending},
+		})
+		if err != nil {
+			return batchItemErrors(pending, byID, err)
+		}
+
+		pending = out.UnprocessedItems[o.tableName]
+		if len(pending) == 0 {
+			return nil
+		}
+		if attempt >= batchMaxRetries {
+			return batchItemErrors(pending, byID, errUnprocessed)
+		}
+
+		if err := o.sleep(delay); err != nil {
+			return batchItemErrors(pending, byID, err)
+		}
+		delay *= 2
+	}
+}
+
+// sleep waits for d, returning early with the context's error if it is canceled
+func (o *Operator) sleep(d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-o.Context().Done():
+		return o.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// batchItemErrors reports every pending write request as failed with err
+func batchItemErrors(pending []types.WriteRequest, byID map[string]batchRequest, err error) []*BatchItemError {
+	errs := make([]*BatchItemError, 0, len(pending))
+	for _, request := range pending {
+		id := writeRequestID(request)
+		errs = append(errs, &BatchItemError{Index: byID[id].index, ID: id, Err: err})
+	}
+	return errs
+}
+
+// writeRequestID returns the ID of the item a write request targets
+func writeRequestID(request types.WriteRequest) string {
+	var item map[string]types.AttributeValue
+	switch {
+	case request.PutRequest != nil:
+		item = request.PutRequest.Item
+	case request.DeleteRequest != nil:
+		item = request.DeleteRequest.Key
+	}
+
+	if id, ok := item["ID"].(*types.AttributeValueMemberS); ok {
+		return id.Value
+	}
+	return ""
+}
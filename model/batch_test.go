@@ -0,0 +1,155 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func batchOfSize(n int) interface{} {
+	return mock.MatchedBy(func(in *dynamodb.BatchWriteItemInput) bool {
+		return len(in.RequestItems["test-table"]) == n
+	})
+}
+
+func TestOperator_BatchWrite(t *testing.T) {
+	delay := batchRetryDelay
+	batchRetryDelay = 0
+	t.Cleanup(func() { batchRetryDelay = delay })
+
+	tests := []struct {
+		name          string
+		setupMock     func(*mocks.DynamoDBAPI)
+		input         func() *[]TestUser
+		operation     func(*Operator, *[]TestUser) *Operator
+		expectFailed  []int
+		errorContains string
+	}{
+		{
+			name: "create_many_chunks",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("BatchWriteItem", mock.Anything, batchOfSize(25), mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, nil).Once()
+				dbMock.On("BatchWriteItem", mock.Anything, batchOfSize(5), mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, nil).Once()
+			},
+			input: func() *[]TestUser {
+				users := make([]TestUser, 30)
+				return &users
+			},
+			operation: func(op *Operator, users *[]TestUser) *Operator {
+				return op.CreateMany(users)
+			},
+		},
+		{
+			name: "retries_unprocessed_items",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("BatchWriteItem", mock.Anything, batchOfSize(3), mock.Anything).Return(
+					func(_ context.Context, in *dynamodb.BatchWriteItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+						return &dynamodb.BatchWriteItemOutput{
+							UnprocessedItems: map[string][]types.WriteRequest{"test-table": in.RequestItems["test-table"][1:2]},
+						}, nil
+					}).Once()
+				dbMock.On("BatchWriteItem", mock.Anything, batchOfSize(1), mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, nil).Once()
+			},
+			input: func() *[]TestUser {
+				users := make([]TestUser, 3)
+				return &users
+			},
+			operation: func(op *Operator, users *[]TestUser) *Operator {
+				return op.CreateMany(users)
+			},
+		},
+		{
+			name: "reports_items_that_stay_unprocessed",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("BatchWriteItem", mock.Anything, mock.Anything, mock.Anything).Return(
+					func(_ context.Context, in *dynamodb.BatchWriteItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+						pending := in.RequestItems["test-table"]
+						return &dynamodb.BatchWriteItemOutput{
+							UnprocessedItems: map[string][]types.WriteRequest{"test-table": pending[len(pending)-1:]},
+						}, nil
+					})
+			},
+			input: func() *[]TestUser {
+				users := make([]TestUser, 3)
+				for i := range users {
+					users[i].ID = fmt.Sprint(i)
+					users[i].Type = "test_user"
+				}
+				return &users
+			},
+			operation: func(op *Operator, users *[]TestUser) *Operator {
+				return op.DeleteMany(users)
+			},
+			expectFailed:  []int{2},
+			errorContains: "not processed",
+		},
+		{
+			name: "reports_invalid_items_and_writes_the_rest",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("BatchWriteItem", mock.Anything, batchOfSize(1), mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, nil).Once()
+			},
+			input: func() *[]TestUser {
+				return &[]TestUser{{Model: Model{ID: "existing"}}, {}}
+			},
+			operation: func(op *Operator, users *[]TestUser) *Operator {
+				return op.CreateMany(users)
+			},
+			expectFailed:  []int{0},
+			errorContains: "item already exists",
+		},
+		{
+			name: "request_failure_fails_chunk",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("BatchWriteItem", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("dynamodb error"))
+			},
+			input: func() *[]TestUser {
+				users := make([]TestUser, 2)
+				return &users
+			},
+			operation: func(op *Operator, users *[]TestUser) *Operator {
+				return op.SaveMany(users)
+			},
+			expectFailed:  []int{0, 1},
+			errorContains: "dynamodb error",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			if tc.setupMock != nil {
+				tc.setupMock(mockDB)
+			}
+
+			op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+			users := tc.input()
+			result := tc.operation(op, users)
+
+			if len(tc.expectFailed) == 0 {
+				require.NoError(t, result.Err)
+				for _, user := range *users {
+					require.NotEmpty(t, user.ID)
+				}
+				return
+			}
+
+			require.Error(t, result.Err)
+			require.Contains(t, result.Err.Error(), tc.errorContains)
+
+			var batchErr *BatchError
+			require.True(t, errors.As(result.Err, &batchErr))
+			failed := make([]int, len(batchErr.Items))
+			for i, item := range batchErr.Items {
+				failed[i] = item.Index
+			}
+			require.Equal(t, tc.expectFailed, failed)
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package model
+
+import (
+	"bytes"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Comparison is a condition on a single field, passed in place of a value to the Where queries
+// e.g. mm.WhereV4(false, &dogs, "Age", model.Gt(3))
+type Comparison struct {
+	op     string
+	values []interface{}
+	// size compares the length of the field instead of its value
+	size bool
+}
+
+const (
+	opEq         = "="
+	opNe         = "<>"
+	opGt         = ">"
+	opGte        = ">="
+	opLt         = "<"
+	opLte        = "<="
+	opBetween    = "BETWEEN"
+	opBeginsWith = "begins_with"
+	opContains   = "contains"
+	opExists     = "attribute_exists"
+	opNotExists  = "attribute_not_exists"
+)
+
+// Eq matches fields equal to v
+func Eq(v interface{}) Comparison { return Comparison{op: opEq, values: []interface{}{v}} }
+
+// Ne matches fields not equal to v
+func Ne(v interface{}) Comparison { return Comparison{op: opNe, values: []interface{}{v}} }
+
+// Gt matches fields greater than v
+func Gt(v interface{}) Comparison { return Comparison{op: opGt, values: []interface{}{v}} }
+
+// Gte matches fields greater than or equal to v
+func Gte(v interface{}) Comparison { return Comparison{op: opGte, values: []interface{}{v}} }
+
+// Lt matches fields less than v
+func Lt(v interface{}) Comparison { return Comparison{op: opLt, values: []interface{}{v}} }
+
+// Lte matches fields less than or equal to v
+func Lte(v interface{}) Comparison { return Comparison{op: opLte, values: []interface{}{v}} }
+
+// Between matches fields from low to high, inclusive
+func Between(low, high interface{}) Comparison {
+	return Comparison{op: opBetween, values: []interface{}{low, high}}
+}
+
+// BeginsWith matches string fields starting with prefix
+func BeginsWith(prefix string) Comparison {
+	return Comparison{op: opBeginsWith, values: []interface{}{prefix}}
+}
+
+// Contains matches string fields containing v as a substring, and slices containing v as an element
+func Contains(v interface{}) Comparison { return Comparison{op: opContains, values: []interface{}{v}} }
+
+// Exists matches items where the field is set. Nil pointers, slices and maps count as not set
+func Exists() Comparison { return Comparison{op: opExists} }
+
+// NotExists matches items where the field is missing or nil
+func NotExists() Comparison { return Comparison{op: opNotExists} }
+
+// Size applies c to the length of the field rather than its value, e.g. model.Size(model.Gt(2))
+// The length of a string is its size in bytes, as DynamoDB measures it
+func Size(c Comparison) Comparison {
+	c.size = true
+	return c
+}
+
+// on returns the comparison with its values as they are stored in attribute
+func (c Comparison) on(attribute string) Comparison {
+	if !isTimestamp(attribute) || c.size {
+		return c
+	}
+	values := make([]interface{}, len(c.values))
+	for i, v := range c.values {
+		values[i] = timestampValue(v)
+	}
+	c.values = values
+	return c
+}
+
+// condition compiles the comparison on the named field into a DynamoDB condition
+func (c Comparison) condition(field string) (expression.ConditionBuilder, error) {
+	c = c.on(field)
+	name := expression.Name(field)
+	var operand expression.OperandBuilder = name
+	if c.size {
+		switch c.op {
+		case opBeginsWith, opContains, opExists, opNotExists:
+			return expression.ConditionBuilder{}, validationErrorf("%s cannot be applied to the size of %s", c.op, field)
+		}
+		operand = name.Size()
+	}
+
+	switch c.op {
+	case opEq:
+		if !c.size {
+			return equalCondition(name, c.values[0]), nil
+		}
+		return expression.Equal(operand, expression.Value(c.values[0])), nil
+	case opNe:
+		return expression.NotEqual(operand, expression.Value(c.values[0])), nil
+	case opGt:
+		return expression.GreaterThan(operand, expression.Value(c.values[0])), nil
+	case opGte:
+		return expression.GreaterThanEqual(operand, expression.Value(c.values[0])), nil
+	case opLt:
+		return expression.LessThan(operand, expression.Value(c.values[0])), nil
+	case opLte:
+		return expression.LessThanEqual(operand, expression.Value(c.values[0])), nil
+	case opBetween:
+		return expression.Between(operand, expression.Value(c.values[0]), expression.Value(c.values[1])), nil
+	case opBeginsWith:
+		return name.BeginsWith(c.values[0].(string)), nil
+	case opContains:
+		return expression.Contains(name, c.values[0]), nil
+	case opExists:
+		// A nil field is stored as NULL, which counts as not set just like in memory
+		return name.AttributeExists().And(expression.Not(name.AttributeType(expression.Null))), nil
+	case opNotExists:
+		return name.AttributeNotExists().Or(name.AttributeType(expression.Null)), nil
+	}
+	return expression.ConditionBuilder{}, validationErrorf("unsupported comparison %q on %s", c.op, field)
+}
+
+// equalCondition compiles an equality between the attribute name and v. Empty strings and binary
+// values also match a missing attribute, since marshalItem leaves them out for indexed fields
+func equalCondition(name expression.NameBuilder, v interface{}) expression.ConditionBuilder {
+	if isEmptyKey(reflect.ValueOf(v)) {
+		return name.AttributeNotExists().Or(name.Equal(expression.Value(v)))
+	}
+	return name.Equal(expression.Value(v))
+}
+
+// keyCondition compiles the comparison into a key condition on the sort key of an index
+// It reports false for comparisons DynamoDB cannot apply to a key
+func (c Comparison) keyCondition(attribute string) (expression.KeyConditionBuilder, bool) {
+	if c.size {
+		return expression.KeyConditionBuilder{}, false
+	}
+	c = c.on(attribute)
+
+	key := expression.Key(attribute)
+	switch c.op {
+	case opEq:
+		return key.Equal(expression.Value(c.values[0])), true
+	case opGt:
+		return key.GreaterThan(expression.Value(c.values[0])), true
+	case opGte:
+		return key.GreaterThanEqual(expression.Value(c.values[0])), true
+	case opLt:
+		return key.LessThan(expression.Value(c.values[0])), true
+	case opLte:
+		return key.LessThanEqual(expression.Value(c.values[0])), true
+	case opBetween:
+		return key.Between(expression.Value(c.values[0]), expression.Value(c.values[1])), true
+	case opBeginsWith:
+		return key.BeginsWith(c.values[0].(string)), true
+	}
+	return expression.KeyConditionBuilder{}, false
+}
+
+// match evaluates the comparison against a field in memory, following DynamoDB's semantics:
+// values of different types never compare, strings compare byte by byte and times compare
+// as the RFC 3339 strings they are stored as
+func (c Comparison) match(field reflect.Value) bool {
+	for field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+		if field.IsNil() {
+			return c.op == opNotExists || c.op == opNe
+		}
+		field = field.Elem()
+	}
+
+	switch c.op {
+	case opExists:
+		return !isNilValue(field)
+	case opNotExists:
+		return isNilValue(field)
+	}
+
+	if c.size {
+		switch field.Kind() {
+		case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+			field = reflect.ValueOf(field.Len())
+		default:
+			return false
+		}
+	}
+
+	switch c.op {
+	case opEq, opNe:
+		order, ok := compareOrdered(field, c.values[0])
+		equal := ok && order == 0 || !ok && reflect.DeepEqual(field.Interface(), c.values[0])
+		return equal == (c.op == opEq)
+	case opGt, opGte, opLt, opLte:
+		order, ok := compareOrdered(field, c.values[0])
+		if !ok {
+			return false
+		}
+		switch c.op {
+		case opGt:
+			return order > 0
+		case opGte:
+			return order >= 0
+		case opLt:
+			return order < 0
+		default:
+			return order <= 0
+		}
+	case opBetween:
+		low, okLow := compareOrdered(field, c.values[0])
+		high, okHigh := compareOrdered(field, c.values[1])
+		return okLow && okHigh && low >= 0 && high <= 0
+	case opBeginsWith:
+		return field.Kind() == reflect.String && strings.HasPrefix(field.String(), c.values[0].(string))
+	case opContains:
+		return containsValue(field, c.values[0])
+	}
+	return false
+}
+
+// isNilValue reports whether a slice or map is nil, which is stored as NULL
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.IsNil()
+	}
+	return false
+}
+
+// scalar reduces a value to the form DynamoDB compares it in: a float64 for numbers,
+// a string for strings and times, or a byte slice for binary values
+func scalar(v reflect.Value) (interface{}, bool) {
+	if !v.CanInterface() {
+		return nil, false
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339Nano), true
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		return v.String(), true
+	case v.CanInt():
+		return float64(v.Int()), true
+	case v.CanUint():
+		return float64(v.Uint()), true
+	case v.CanFloat():
+		return v.Float(), true
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		return v.Bytes(), true
+	}
+	return nil, false
+}
+
+// compareOrdered compares field against v, returning -1, 0 or 1
+// It reports false when the two values are not of the same comparable type
+func compareOrdered(field reflect.Value, v interface{}) (int, bool) {
+	if v == nil {
+		return 0, false
+	}
+	left, ok := scalar(field)
+	if !ok {
+		return 0, false
+	}
+	right, ok := scalar(reflect.ValueOf(v))
+	if !ok {
+		return 0, false
+	}
+
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case l < r:
+			return -1, true
+		case l > r:
+			return 1, true
+		}
+		return 0, true
+	case string:
+		r, ok := right.(string)
+		return strings.Compare(l, r), ok
+	case []byte:
+		r, ok := right.([]byte)
+		return bytes.Compare(l, r), ok
+	}
+	return 0, false
+}
+
+// containsValue reports whether a string contains v as a substring or a list contains v as an element
+func containsValue(field reflect.Value, v interface{}) bool {
+	switch field.Kind() {
+	case reflect.String:
+		s, ok := v.(string)
+		return ok && strings.Contains(field.String(), s)
+	case reflect.Slice, reflect.Array:
+		// Byte slices are stored as binary values, which DynamoDB's contains does not search
+		if field.Type().Elem().Kind() == reflect.Uint8 {
+			return false
+		}
+		for i := 0; i < field.Len(); i++ {
+			if order, ok := compareOrdered(field.Index(i), v); ok && order == 0 {
+				return true
+			}
+			if reflect.DeepEqual(field.Index(i).Interface(), v) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComparison_Condition(t *testing.T) {
+	tests := []struct {
+		name          string
+		comparison    Comparison
+		expected      string
+		errorContains string
+	}{
+		{name: "eq", comparison: Eq(3), expected: "#0 = :0"},
+		{name: "ne", comparison: Ne(3), expected: "#0 <> :0"},
+		{name: "gt", comparison: Gt(3), expected: "#0 > :0"},
+		{name: "gte", comparison: Gte(3), expected: "#0 >= :0"},
+		{name: "lt", comparison: Lt(3), expected: "#0 < :0"},
+		{name: "lte", comparison: Lte(3), expected: "#0 <= :0"},
+		{name: "between", comparison: Between(1, 5), expected: "#0 BETWEEN :0 AND :1"},
+		{name: "begins_with", comparison: BeginsWith("Lab"), expected: "begins_with (#0, :0)"},
+		{name: "contains", comparison: Contains("toy"), expected: "contains (#0, :0)"},
+		{name: "exists", comparison: Exists(), expected: "(attribute_exists (#0)) AND (NOT (attribute_type (#0, :0)))"},
+		{name: "not_exists", comparison: NotExists(), expected: "(attribute_not_exists (#0)) OR (attribute_type (#0, :0))"},
+		{name: "size", comparison: Size(Gt(2)), expected: "size (#0) > :0"},
+		{name: "size_of_begins_with", comparison: Size(BeginsWith("a")), errorContains: "cannot be applied to the size"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cond, err := tc.comparison.condition("Age")
+			if tc.errorContains != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.errorContains)
+				return
+			}
+			require.NoError(t, err)
+
+			expr, err := expression.NewBuilder().WithCondition(cond).Build()
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, aws.ToString(expr.Condition()))
+		})
+	}
+}
+
+func TestComparison_Match(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	name := "Buddy"
+
+	tests := []struct {
+		name       string
+		value      interface{}
+		comparison Comparison
+		expected   bool
+	}{
+		{name: "gt_int", value: 5, comparison: Gt(3), expected: true},
+		{name: "gt_mixed_numbers", value: int64(5), comparison: Gt(4.5), expected: true},
+		{name: "gt_type_mismatch", value: 5, comparison: Gt("3"), expected: false},
+		{name: "gte_equal", value: 3, comparison: Gte(3), expected: true},
+		{name: "lt_string", value: "apple", comparison: Lt("banana"), expected: true},
+		{name: "lte_false", value: 4, comparison: Lte(3), expected: false},
+		{name: "ne", value: "a", comparison: Ne("b"), expected: true},
+		{name: "ne_type_mismatch", value: 3, comparison: Ne("3"), expected: true},
+		{name: "eq_slice", value: []string{"a"}, comparison: Eq([]string{"a"}), expected: true},
+		{name: "between_inclusive", value: 5, comparison: Between(1, 5), expected: true},
+		{name: "between_outside", value: 6, comparison: Between(1, 5), expected: false},
+		{name: "between_time", value: created, comparison: Between(created.Add(-time.Hour), created.Add(time.Hour)), expected: true},
+		{name: "begins_with", value: "Labrador", comparison: BeginsWith("Lab"), expected: true},
+		{name: "begins_with_number", value: 123, comparison: BeginsWith("1"), expected: false},
+		{name: "contains_substring", value: "Labrador", comparison: Contains("bra"), expected: true},
+		{name: "contains_element", value: []string{"ball", "bone"}, comparison: Contains("bone"), expected: true},
+		{name: "contains_missing_element", value: []int{1, 2}, comparison: Contains(3), expected: false},
+		{name: "exists_pointer", value: &name, comparison: Exists(), expected: true},
+		{name: "exists_nil_pointer", value: (*string)(nil), comparison: Exists(), expected: false},
+		{name: "not_exists_nil_slice", value: []string(nil), comparison: NotExists(), expected: true},
+		{name: "not_exists_set", value: "set", comparison: NotExists(), expected: false},
+		{name: "pointer_dereferenced", value: &name, comparison: BeginsWith("Bud"), expected: true},
+		{name: "size_of_string", value: "héllo", comparison: Size(Eq(6)), expected: true},
+		{name: "size_of_slice", value: []int{1, 2, 3}, comparison: Size(Gt(2)), expected: true},
+		{name: "size_of_map", value: map[string]int{"a": 1}, comparison: Size(Between(2, 4)), expected: false},
+		{name: "size_of_number", value: 12, comparison: Size(Gt(0)), expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, compareValues(reflect.ValueOf(tc.value), tc.comparison))
+		})
+	}
+}
+
+func TestOperator_WhereV4_Comparisons(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupMock func(*mocks.DynamoDBAPI)
+		operation func(*Operator, *[]TestDog) *Operator
+	}{
+		{
+			name: "range_on_index_uses_key_condition",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return aws.ToString(in.IndexName) == "age-index" &&
+						strings.Contains(aws.ToString(in.KeyConditionExpression), "BETWEEN")
+				}), mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+			},
+			operation: func(op *Operator, dogs *[]TestDog) *Operator {
+				return op.WhereV4(false, dogs, "Age", Between(2, 5))
+			},
+		},
+		{
+			name: "filter_only_comparison",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return in.IndexName == nil && strings.HasPrefix(aws.ToString(in.FilterExpression), "(contains (")
+				}), mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+			},
+			operation: func(op *Operator, dogs *[]TestDog) *Operator {
+				return op.WhereV4(false, dogs, "Name", Contains("udd"))
+			},
+		},
+		{
+			name: "where_comparison",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, indexQuery("breed-index"), mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+			},
+			operation: func(op *Operator, dogs *[]TestDog) *Operator {
+				return op.Where(dogs, "Breed", BeginsWith("Lab"))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			tc.setupMock(mockDB)
+
+			op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+			require.NoError(t, tc.operation(op, &[]TestDog{}).Err)
+		})
+	}
+}
+
+func TestOperator_WhereV3_Comparisons(t *testing.T) {
+	op := NewMagicModelOperatorWithClient(mocks.NewDynamoDBAPI(t), "test-table")
+	dogs := []TestDog{{Name: "Buddy", Age: 2}, {Name: "Fido", Age: 7}, {Name: "Rex", Age: 4}}
+
+	result := op.WhereV3(true, &dogs, "Age", Gte(4)).WhereV3(false, &dogs, "Name", BeginsWith("R"))
+	require.NoError(t, result.Err)
+	require.Len(t, dogs, 1)
+	require.Equal(t, "Rex", dogs[0].Name)
+}
@@ -0,0 +1,138 @@
+package model
+
+import (
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"reflect"
+)
+
+// Condition is a boolean tree of field conditions built with Field, And, Or and Not
+// It compiles to a single DynamoDB filter expression, and Match evaluates it the same way in memory
+type Condition interface {
+	// Match reports whether item, a model or a pointer to one, satisfies the condition
+	Match(item interface{}) bool
+
+	// condition compiles the condition, naming attributes as fields maps them
+	condition(fields *fieldMap) (expression.ConditionBuilder, error)
+	matchValue(item reflect.Value) bool
+}
+
+// Field matches items whose field equals value. As with WhereV4, a slice matches any of its
+// elements and a Comparison such as model.Gt(3) is applied to the field
+// Nested fields are separated by dots, e.g. "Home.Address.City"
+func Field(name string, value interface{}) Condition {
+	return fieldCondition{name: name, values: normalizeFieldValues(value)}
+}
+
+// And matches items that satisfy every one of conditions
+func And(conditions ...Condition) Condition {
+	return groupCondition{and: true, conditions: conditions}
+}
+
+// Or matches items that satisfy at least one of conditions
+func Or(conditions ...Condition) Condition {
+	return groupCondition{conditions: conditions}
+}
+
+// Not matches items that do not satisfy c
+func Not(c Condition) Condition {
+	return notCondition{c}
+}
+
+type fieldCondition struct {
+	name   string
+	values []interface{}
+}
+
+func (f fieldCondition) Match(item interface{}) bool {
+	return f.matchValue(reflect.ValueOf(item))
+}
+
+func (f fieldCondition) condition(fields *fieldMap) (expression.ConditionBuilder, error) {
+	if len(f.values) == 0 {
+		return expression.ConditionBuilder{}, validationErrorf("no values given for %s", f.name)
+	}
+	return buildFieldCondition(fields, f.name, f.values)
+}
+
+func (f fieldCondition) matchValue(item reflect.Value) bool {
+	value, found := lookupField(item, f.name)
+	for _, v := range f.values {
+		comparison, ok := v.(Comparison)
+		if !ok {
+			comparison = Eq(v)
+		}
+
+		// DynamoDB treats a missing attribute as not existing and not equal to anything
+		if !found {
+			if comparison.op == opNotExists || comparison.op == opNe {
+				return true
+			}
+			continue
+		}
+		if comparison.match(value) {
+			return true
+		}
+	}
+	return false
+}
+
+type groupCondition struct {
+	and        bool
+	conditions []Condition
+}
+
+func (g groupCondition) Match(item interface{}) bool {
+	return g.matchValue(reflect.ValueOf(item))
+}
+
+func (g groupCondition) condition(fields *fieldMap) (expression.ConditionBuilder, error) {
+	if len(g.conditions) == 0 {
+		return expression.ConditionBuilder{}, validationErrorf("And and Or need at least one condition")
+	}
+
+	built := make([]expression.ConditionBuilder, len(g.conditions))
+	for i, c := range g.conditions {
+		cond, err := c.condition(fields)
+		if err != nil {
+			return expression.ConditionBuilder{}, err
+		}
+		built[i] = cond
+	}
+
+	if len(built) == 1 {
+		return built[0], nil
+	}
+	if g.and {
+		return expression.And(built[0], built[1], built[2:]...), nil
+	}
+	return expression.Or(built[0], built[1], built[2:]...), nil
+}
+
+func (g groupCondition) matchValue(item reflect.Value) bool {
+	for _, c := range g.conditions {
+		if c.matchValue(item) != g.and {
+			return !g.and
+		}
+	}
+	return g.and
+}
+
+type notCondition struct {
+	c Condition
+}
+
+func (n notCondition) Match(item interface{}) bool {
+	return n.matchValue(reflect.ValueOf(item))
+}
+
+func (n notCondition) condition(fields *fieldMap) (expression.ConditionBuilder, error) {
+	cond, err := n.c.condition(fields)
+	if err != nil {
+		return expression.ConditionBuilder{}, err
+	}
+	return expression.Not(cond), nil
+}
+
+func (n notCondition) matchValue(item reflect.Value) bool {
+	return !n.c.matchValue(item)
+}
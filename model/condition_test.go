@@ -0,0 +1,150 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCondition_Build(t *testing.T) {
+	tests := []struct {
+		name          string
+		condition     Condition
+		expected      string
+		errorContains string
+	}{
+		{
+			name:      "field",
+			condition: Field("Breed", "Lab"),
+			expected:  "#0 = :0",
+		},
+		{
+			name:      "field_in",
+			condition: Field("Breed", []string{"Lab", "Beagle"}),
+			expected:  "#0 IN (:0, :1)",
+		},
+		{
+			name: "nested_groups",
+			condition: Or(
+				And(Field("Breed", "Lab"), Field("Age", Gt(3))),
+				Field("Status", "adopted"),
+			),
+			expected: "((#0 = :0) AND (#1 > :1)) OR (#2 = :2)",
+		},
+		{
+			name:      "not",
+			condition: Not(Field("Age", Between(1, 3))),
+			expected:  "NOT (#0 BETWEEN :0 AND :1)",
+		},
+		{
+			name:      "single_member_group",
+			condition: And(Field("Age", Lt(3))),
+			expected:  "#0 < :0",
+		},
+		{
+			name:          "empty_group",
+			condition:     Or(),
+			errorContains: "at least one condition",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cond, err := tc.condition.condition(nil)
+			if tc.errorContains != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.errorContains)
+				return
+			}
+			require.NoError(t, err)
+
+			expr, err := expression.NewBuilder().WithCondition(cond).Build()
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, aws.ToString(expr.Condition()))
+		})
+	}
+}
+
+func TestCondition_Match(t *testing.T) {
+	type Address struct {
+		City string
+	}
+	type Pet struct {
+		Breed  string
+		Age    int
+		Status string
+		Home   *Address
+	}
+
+	adoptable := Or(
+		And(Field("Breed", "Lab"), Field("Age", Gt(3))),
+		Field("Status", "adopted"),
+	)
+
+	tests := []struct {
+		name      string
+		condition Condition
+		item      interface{}
+		expected  bool
+	}{
+		{name: "first_branch", condition: adoptable, item: Pet{Breed: "Lab", Age: 4}, expected: true},
+		{name: "second_branch", condition: adoptable, item: &Pet{Breed: "Beagle", Status: "adopted"}, expected: true},
+		{name: "no_branch", condition: adoptable, item: Pet{Breed: "Lab", Age: 2}, expected: false},
+		{name: "not", condition: Not(adoptable), item: Pet{Breed: "Lab", Age: 2}, expected: true},
+		{name: "in", condition: Field("Breed", []string{"Lab", "Beagle"}), item: Pet{Breed: "Beagle"}, expected: true},
+		{name: "strict_types", condition: Field("Age", "4"), item: Pet{Age: 4}, expected: false},
+		{name: "nested_field", condition: Field("Home.City", "Paris"), item: Pet{Home: &Address{City: "Paris"}}, expected: true},
+		{name: "nil_parent_is_missing", condition: Field("Home.City", NotExists()), item: Pet{}, expected: true},
+		{name: "missing_is_not_equal", condition: Not(Field("Home.City", "Paris")), item: Pet{}, expected: true},
+		{name: "empty_and", condition: And(), item: Pet{}, expected: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.condition.Match(tc.item))
+		})
+	}
+}
+
+func TestOperator_Conditions(t *testing.T) {
+	adoptable := Or(And(Field("Breed", "Lab"), Field("Age", Gt(3))), Field("Name", "Rex"))
+	orFilter := mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return len(in.ExpressionAttributeNames) == 5 && len(in.ExpressionAttributeValues) == 5
+	})
+
+	t.Run("where_v4", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("Query", mock.Anything, orFilter, mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+
+		op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+		require.NoError(t, op.WhereV4(false, &[]TestDog{}, "", adoptable).Err)
+	})
+
+	t.Run("query_filter", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("Query", mock.Anything, orFilter, mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+
+		op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+		require.NoError(t, op.Query(&[]TestDog{}).Filter(adoptable).Exec(context.Background()))
+	})
+
+	t.Run("where_v3_in_memory", func(t *testing.T) {
+		op := NewMagicModelOperatorWithClient(mocks.NewDynamoDBAPI(t), "test-table")
+		dogs := []TestDog{
+			{Name: "Buddy", Breed: "Lab", Age: 5},
+			{Name: "Fido", Breed: "Lab", Age: 2},
+			{Name: "Rex", Breed: "Beagle", Age: 1},
+		}
+
+		require.NoError(t, op.WhereV3(false, &dogs, "", adoptable).Err)
+		require.Len(t, dogs, 2)
+		require.Equal(t, "Buddy", dogs[0].Name)
+		require.Equal(t, "Rex", dogs[1].Name)
+	})
+}
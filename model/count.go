@@ -0,0 +1,90 @@
+package model
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+)
+
+// CountResult is the outcome of a Count query
+type CountResult struct {
+	// Count is the number of items that matched every condition
+	Count int64
+	// ScannedCount is the number of items DynamoDB read before applying the filter
+	ScannedCount int64
+	// ConsumedCapacity is the total read capacity consumed across every page
+	ConsumedCapacity float64
+}
+
+// Count returns how many items of the model q matches every one of conditions, without loading them
+// q is a model value or pointer, e.g. mm.Count(Dog{}, model.Field("Breed", "Lab"), model.Field("Age", model.Gt(3)))
+// Soft deleted items are not counted. When the operator's MaxPages limit is reached the count
+// read so far is returned alongside ErrQueryLimitReached
+func (o *Operator) Count(q interface{}, conditions ...Condition) (CountResult, error) {
+	if o.Err != nil {
+		return CountResult{}, o.Err
+	}
+
+	name, err := ParseModelName(q)
+	if err != nil {
+		return CountResult{}, err
+	}
+
+	t := reflect.TypeOf(q)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	err = ValidateInput(reflect.New(t).Interface(), "Count", name)
+	if err != nil {
+		return CountResult{}, err
+	}
+
+	whereConditions := make([]WhereV4Condition, len(conditions))
+	for i, c := range conditions {
+		// Plain field conditions are passed by name so they can use an index
+		if field, ok := c.(fieldCondition); ok {
+			whereConditions[i] = WhereV4Condition{FieldName: field.name, FieldValues: field.values}
+			continue
+		}
+		whereConditions[i] = WhereV4Condition{FieldValues: []interface{}{c}}
+	}
+
+	expr, index, err := planWhereV4(q, name, whereConditions)
+	if err != nil {
+		return CountResult{}, newOperationError("Count", q, err)
+	}
+
+	params := &dynamodb.QueryInput{
+		TableName:                 aws.String(o.tableName),
+		IndexName:                 index,
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		Select:                    types.SelectCount,
+		ReturnConsumedCapacity:    types.ReturnConsumedCapacityTotal,
+	}
+
+	var result CountResult
+	for pages := 1; ; pages++ {
+		response, err := o.db.Query(o.Context(), params)
+		if err != nil {
+			return CountResult{}, newOperationError("Count", q, err)
+		}
+		result.Count += int64(response.Count)
+		result.ScannedCount += int64(response.ScannedCount)
+		if response.ConsumedCapacity != nil {
+			result.ConsumedCapacity += aws.ToFloat64(response.ConsumedCapacity.CapacityUnits)
+		}
+
+		if len(response.LastEvaluatedKey) == 0 {
+			return result, nil
+		}
+		if o.limits.MaxPages > 0 && pages >= o.limits.MaxPages {
+			return result, newOperationError("Count", q, fmt.Errorf("%w: stopped after %d pages", ErrQueryLimitReached, pages))
+		}
+		params.ExclusiveStartKey = response.LastEvaluatedKey
+	}
+}
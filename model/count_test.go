@@ -0,0 +1,134 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPlainStruct is a named struct that does not embed Model
+type TestPlainStruct struct {
+	Name string
+}
+
+func countPage(count int32, capacity float64, more bool) *dynamodb.QueryOutput {
+	out := &dynamodb.QueryOutput{
+		Count:            count,
+		ScannedCount:     count * 2,
+		ConsumedCapacity: &types.ConsumedCapacity{CapacityUnits: aws.Float64(capacity)},
+	}
+	if more {
+		out.LastEvaluatedKey = map[string]types.AttributeValue{"ID": &types.AttributeValueMemberS{Value: "next"}}
+	}
+	return out
+}
+
+func TestOperator_Count(t *testing.T) {
+	isCount := mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return in.Select == types.SelectCount && in.ProjectionExpression == nil &&
+			in.ReturnConsumedCapacity == types.ReturnConsumedCapacityTotal
+	})
+
+	tests := []struct {
+		name        string
+		q           interface{}
+		conditions  []Condition
+		limits      QueryLimits
+		setupMock   func(*mocks.DynamoDBAPI)
+		expect      CountResult
+		expectErr   string
+		expectLimit bool
+	}{
+		{
+			name:       "follows_pages",
+			q:          TestUser{},
+			conditions: []Condition{Field("Age", Gt(3))},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, isCount, mock.Anything).Return(countPage(3, 0.5, true), nil).Once()
+				dbMock.On("Query", mock.Anything, isCount, mock.Anything).Return(countPage(2, 0.5, false), nil).Once()
+			},
+			expect: CountResult{Count: 5, ScannedCount: 10, ConsumedCapacity: 1},
+		},
+		{
+			name:       "uses_index",
+			q:          &TestDog{},
+			conditions: []Condition{Field("Breed", "Lab"), Or(Field("Age", 1), Field("Age", 2))},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, indexQuery("breed-index"), mock.Anything).Return(countPage(4, 0.5, false), nil).Once()
+			},
+			expect: CountResult{Count: 4, ScannedCount: 8, ConsumedCapacity: 0.5},
+		},
+		{
+			name:   "stops_at_max_pages",
+			q:      TestUser{},
+			limits: QueryLimits{MaxPages: 1},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, isCount, mock.Anything).Return(countPage(3, 0.5, true), nil).Once()
+			},
+			expect:      CountResult{Count: 3, ScannedCount: 6, ConsumedCapacity: 0.5},
+			expectLimit: true,
+		},
+		{
+			name: "query_error",
+			q:    TestUser{},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, isCount, mock.Anything).Return(nil, errors.New("boom")).Once()
+			},
+			expectErr: "encountered an error during Count operation: boom",
+		},
+		{
+			name:      "unnamed_struct",
+			q:         struct{ Name string }{},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			expectErr: "cannot use an unnamed struct",
+		},
+		{
+			name:      "missing_model",
+			q:         TestPlainStruct{},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			expectErr: "must embed model.Model",
+		},
+		{
+			name:       "invalid_condition",
+			q:          TestUser{},
+			conditions: []Condition{Or()},
+			setupMock:  func(dbMock *mocks.DynamoDBAPI) {},
+			expectErr:  "And and Or need at least one condition",
+		},
+		{
+			name:       "empty_value_list",
+			q:          TestDog{},
+			conditions: []Condition{Field("Breed", []string{})},
+			setupMock:  func(dbMock *mocks.DynamoDBAPI) {},
+			expectErr:  "no values given for Breed",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			tc.setupMock(mockDB)
+
+			op := NewMagicModelOperatorWithClient(mockDB, "test-table").WithQueryLimits(tc.limits)
+			result, err := op.Count(tc.q, tc.conditions...)
+
+			switch {
+			case tc.expectErr != "":
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectErr)
+				return
+			case tc.expectLimit:
+				require.ErrorIs(t, err, ErrQueryLimitReached)
+			default:
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expect, result)
+		})
+	}
+}
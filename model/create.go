@@ -4,67 +4,132 @@ import (
 	"context"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/google/uuid"
 	"reflect"
-	"time"
 )
 
-func (o *Operator) Create(q interface{}) *Operator {
+// CreateOption customizes a single Create call
+type CreateOption func(*createOptions)
+
+type createOptions struct {
+	id       string
+	customID bool
+}
+
+// WithID stores the created item under id instead of a generated UUID
+// Create still fails with ErrAlreadyExists if an item with that ID exists
+func WithID(id string) CreateOption {
+	return func(opts *createOptions) {
+		opts.id = id
+		opts.customID = true
+	}
+}
+
+func (o *Operator) Create(q interface{}, opts ...CreateOption) *Operator {
 	if o.Err != nil {
 		return o
 	}
 
-	name, err := ParseModelName(q)
+	w, err := prepareCreate(o.Context(), q, opts...)
 	if err != nil {
 		o.Err = err
 		return o
 	}
 
-	err = ValidateInput(q, "Create", name)
+	_, err = o.db.PutItem(o.Context(), &dynamodb.PutItemInput{
+		TableName:                 aws.String(o.tableName),
+		Item:                      w.item,
+		ConditionExpression:       w.expr.Condition(),
+		ExpressionAttributeNames:  w.expr.Names(),
+		ExpressionAttributeValues: w.expr.Values(),
+	})
+
 	if err != nil {
-		o.Err = err
+		if isConditionFailed(err) {
+			o.Err = newOperationError("Create", q, fmt.Errorf("%w: %s", w.conflict, reflect.ValueOf(q).Elem().FieldByName("ID").String()))
+			return o
+		}
+		o.Err = newOperationError("Create", q, err)
 		return o
 	}
 
+	w.refresh(o.snapshots)
+	err = w.succeeded(o.Context())
+	if err != nil {
+		o.Err = newOperationError("Create", q, err)
+	}
+	return o
+}
+
+// prepareCreate validates q, assigns its Type, ID, timestamps and initial version, runs its BeforeCreate
+// hook, checks the rules declared in its mm tags and marshals it for a put
+// The put is guarded so it never overwrites an existing item with the same ID
+func prepareCreate(ctx context.Context, q interface{}, opts ...CreateOption) (*preparedWrite, error) {
+	name, err := ParseModelName(q)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ValidateInput(q, "Create", name)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &createOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	payload := reflect.ValueOf(q).Elem()
 
-	if payload.FieldByName("ID").String() != "" {
-		o.Err = fmt.Errorf("encountered an error during Create operations: item already exists. try the update method instead")
-		return o
+	if options.customID {
+		if options.id == "" {
+			return nil, newOperationError("Create", q, validationErrorf("ID cannot be empty"))
+		}
+	} else {
+		if payload.FieldByName("ID").String() != "" {
+			return nil, newOperationError("Create", q, validationErrorf("item already exists. try the update method instead, or pass WithID to create it with that ID"))
+		}
+		options.id = uuid.New().String()
 	}
 
-	t := time.Now()
+	t := timestamp()
 
 	payload.FieldByName("Type").SetString(name)
-	payload.FieldByName("ID").SetString(uuid.New().String())
+	payload.FieldByName("ID").SetString(options.id)
 	payload.FieldByName("CreatedAt").Set(reflect.ValueOf(t))
 	payload.FieldByName("UpdatedAt").Set(reflect.ValueOf(t))
 
-	av, err := attributevalue.MarshalMap(q)
+	version, _, err := versionField(payload)
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during Create operations: %v", err)
-		return o
+		return nil, newOperationError("Create", q, err)
+	}
+	if version.IsValid() {
+		version.SetInt(1)
 	}
 
-	// Use the operator's db field if available, otherwise fall back to the global svc
-	dbClient := o.db
-	tableName := o.tableName
-	if dbClient == nil {
-		dbClient = svc
-		tableName = dynamoDBTableName
+	err = callHook(ctx, q, BeforeCreator.BeforeCreate)
+	if err != nil {
+		return nil, newOperationError("Create", q, err)
 	}
 
-	_, err = dbClient.PutItem(context.TODO(), &dynamodb.PutItemInput{
-		TableName: aws.String(tableName),
-		Item:      av,
-	})
+	err = validateModel(payload)
+	if err != nil {
+		return nil, newOperationError("Create", q, err)
+	}
 
+	w := &preparedWrite{conflict: ErrAlreadyExists, after: afterHook(q, AfterCreator.AfterCreate)}
+	w.expr, err = expression.NewBuilder().WithCondition(expression.AttributeNotExists(expression.Name("ID"))).Build()
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during Create operations: %v", err)
-		return o
+		return nil, newOperationError("Create", q, err)
 	}
 
-	return o
+	w.item, err = marshalItem(q)
+	if err != nil {
+		return nil, newOperationError("Create", q, err)
+	}
+	w.snapshot = func(s *snapshots) { s.store(payload, w.item) }
+	return w, nil
 }
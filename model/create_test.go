@@ -3,7 +3,9 @@ package model
 import (
 	"errors"
 	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -17,7 +19,10 @@ func TestOperator_Create(t *testing.T) {
 		setupMock     func(dbMock *mocks.DynamoDBAPI)
 		input         *TestUser
 		presetID      string
+		opts          []CreateOption
+		expectID      string
 		expectError   bool
+		expectErr     error
 		errorContains string
 	}{
 		{
@@ -48,6 +53,43 @@ func TestOperator_Create(t *testing.T) {
 			expectError:   true,
 			errorContains: "item already exists",
 		},
+		{
+			name: "custom_id",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("PutItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+					id, ok := in.Item["ID"].(*types.AttributeValueMemberS)
+					return ok && id.Value == "custom-id" && aws.ToString(in.ConditionExpression) == "attribute_not_exists (#0)"
+				}), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil)
+			},
+			input: &TestUser{
+				Name: "John Doe",
+			},
+			opts:        []CreateOption{WithID("custom-id")},
+			expectID:    "custom-id",
+			expectError: false,
+		},
+		{
+			name: "custom_id_already_exists",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("PutItem", mock.Anything, mock.Anything, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")})
+			},
+			input: &TestUser{
+				Name: "John Doe",
+			},
+			opts:          []CreateOption{WithID("custom-id")},
+			expectError:   true,
+			expectErr:     ErrAlreadyExists,
+			errorContains: "custom-id",
+		},
+		{
+			name: "empty_custom_id",
+			input: &TestUser{
+				Name: "John Doe",
+			},
+			opts:          []CreateOption{WithID("")},
+			expectError:   true,
+			errorContains: "ID cannot be empty",
+		},
 		{
 			name: "other_failure",
 			setupMock: func(dbMock *mocks.DynamoDBAPI) {
@@ -82,7 +124,7 @@ func TestOperator_Create(t *testing.T) {
 			}
 
 			// Call the Create method
-			result := op.Create(tc.input)
+			result := op.Create(tc.input, tc.opts...)
 
 			// Check for errors
 			if tc.expectError {
@@ -90,10 +132,16 @@ func TestOperator_Create(t *testing.T) {
 				if tc.errorContains != "" {
 					assert.Contains(t, result.Err.Error(), tc.errorContains)
 				}
+				if tc.expectErr != nil {
+					assert.ErrorIs(t, result.Err, tc.expectErr)
+				}
 			} else {
 				require.NoError(t, result.Err)
 
 				assert.NotEmpty(t, tc.input.ID, "ID was not set")
+				if tc.expectID != "" {
+					assert.Equal(t, tc.expectID, tc.input.ID)
+				}
 				assert.Equal(t, "test_user", tc.input.Type, "Type was not set correctly")
 				assert.False(t, tc.input.CreatedAt.IsZero(), "CreatedAt was not set")
 				assert.False(t, tc.input.UpdatedAt.IsZero(), "UpdatedAt was not set")
@@ -2,7 +2,6 @@ package model
 
 import (
 	"context"
-	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
@@ -14,34 +13,57 @@ func (o *Operator) Delete(q interface{}) *Operator {
 		return o
 	}
 
-	name, err := ParseModelName(q)
+	w, err := prepareDelete(o.Context(), q)
 	if err != nil {
 		o.Err = err
 		return o
 	}
 
-	err = ValidateInput(q, "Delete", name)
+	_, err = o.db.DeleteItem(o.Context(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(o.tableName), Key: w.key,
+	})
 	if err != nil {
-		o.Err = err
+		o.Err = newOperationError("Delete", q, err)
 		return o
 	}
 
+	w.refresh(o.snapshots)
+	err = w.succeeded(o.Context())
+	if err != nil {
+		o.Err = newOperationError("Delete", q, err)
+	}
+	return o
+}
+
+// prepareDelete validates q, runs its BeforeDelete hook and builds the key of the item to delete
+func prepareDelete(ctx context.Context, q interface{}) (*preparedWrite, error) {
+	name, err := ParseModelName(q)
+	if err != nil {
+		return nil, err
+	}
+
 	err = ValidateInput(q, "Delete", name)
 	if err != nil {
-		o.Err = err
-		return o
+		return nil, err
 	}
 
-	payload := reflect.ValueOf(q).Elem()
-	_, err = svc.DeleteItem(context.TODO(), &dynamodb.DeleteItemInput{
-		TableName: aws.String(dynamoDBTableName), Key: map[string]types.AttributeValue{
-			"ID":   &types.AttributeValueMemberS{Value: payload.FieldByName("ID").String()},
-			"Type": &types.AttributeValueMemberS{Value: payload.FieldByName("Type").String()},
-		},
-	})
+	err = callHook(ctx, q, BeforeDeleter.BeforeDelete)
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during Delete operation: %v", err)
-		return o
+		return nil, newOperationError("Delete", q, err)
+	}
+
+	payload := reflect.ValueOf(q).Elem()
+	return &preparedWrite{
+		key:      modelKey(payload),
+		after:    afterHook(q, AfterDeleter.AfterDelete),
+		snapshot: func(s *snapshots) { s.forget(payload) },
+	}, nil
+}
+
+// modelKey builds the primary key of the item held by payload
+func modelKey(payload reflect.Value) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"ID":   &types.AttributeValueMemberS{Value: payload.FieldByName("ID").String()},
+		"Type": &types.AttributeValueMemberS{Value: payload.FieldByName("Type").String()},
 	}
-	return o
 }
@@ -0,0 +1,232 @@
+package model
+
+import (
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// snapshots holds the marshalled attributes of items as they were last loaded or written,
+// keyed by their Type and ID
+type snapshots struct {
+	mu    sync.Mutex
+	items map[[2]string]map[string]types.AttributeValue
+}
+
+// WithDirtyTracking returns a copy of the operator that remembers the items its Find, FindMany, All,
+// Where, Query and Page calls load. Saving one of them sends an UpdateItem with only the attributes
+// that changed since, down to the fields of nested structs, so it does not overwrite concurrent
+// updates to other attributes. Saving an unchanged item makes no call and skips the AfterSave hook
+// Writes through the operator, including updates, batches and transactions, keep the snapshots current
+// Items that were not loaded or created through the operator are still saved whole
+// Every copy of the returned operator shares the snapshots, which are kept for as long as it lives,
+// so use one per unit of work such as a request
+func (o *Operator) WithDirtyTracking() *Operator {
+	op := *o
+	op.snapshots = &snapshots{items: map[[2]string]map[string]types.AttributeValue{}}
+	return &op
+}
+
+// track snapshots the models q holds, a struct or a slice of structs behind pointers,
+// when dirty tracking is on
+func (o *Operator) track(q interface{}) {
+	if o.snapshots == nil {
+		return
+	}
+	v := reflect.ValueOf(q)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		o.snapshots.record(v)
+		return
+	}
+	for i := 0; i < v.Len(); i++ {
+		o.snapshots.record(v.Index(i))
+	}
+}
+
+// record snapshots the model v holds as it would be marshalled now
+func (s *snapshots) record(v reflect.Value) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || !v.CanAddr() {
+		return
+	}
+	item, err := marshalItem(v.Addr().Interface())
+	if err != nil {
+		// Items that cannot be snapshotted are saved whole
+		return
+	}
+	s.store(v, item)
+}
+
+// store replaces the snapshot of the model payload holds with item
+func (s *snapshots) store(payload reflect.Value, item map[string]types.AttributeValue) {
+	if s == nil {
+		return
+	}
+	key, ok := snapshotKey(payload)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items[key] = item
+}
+
+// update replaces the given attributes in the snapshot of the model payload holds with those of item,
+// dropping the ones item lacks. Models that are not tracked stay untracked
+func (s *snapshots) update(payload reflect.Value, item map[string]types.AttributeValue, attributes []string) {
+	if s == nil {
+		return
+	}
+	key, ok := snapshotKey(payload)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.items[key]
+	if !ok {
+		return
+	}
+
+	updated := make(map[string]types.AttributeValue, len(old))
+	for name, value := range old {
+		updated[name] = value
+	}
+	for _, name := range attributes {
+		if value, ok := item[name]; ok {
+			updated[name] = value
+		} else {
+			delete(updated, name)
+		}
+	}
+	s.items[key] = updated
+}
+
+// lookup returns the snapshot of the model payload holds, nil when it is not tracked
+func (s *snapshots) lookup(payload reflect.Value) map[string]types.AttributeValue {
+	if s == nil {
+		return nil
+	}
+	key, ok := snapshotKey(payload)
+	if !ok {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.items[key]
+}
+
+// forget drops the snapshot of the model payload holds
+func (s *snapshots) forget(payload reflect.Value) {
+	if s == nil {
+		return
+	}
+	key, ok := snapshotKey(payload)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.items, key)
+}
+
+func snapshotKey(payload reflect.Value) ([2]string, bool) {
+	typeName, id := payload.FieldByName("Type"), payload.FieldByName("ID")
+	if !typeName.IsValid() || !id.IsValid() || id.String() == "" {
+		return [2]string{}, false
+	}
+	return [2]string{typeName.String(), id.String()}, true
+}
+
+// diffItems adds the SET and REMOVE actions that turn the attributes old into current to update,
+// leaving out the attributes in skip. It returns the extended update and the number of actions added
+// Maps on both sides, such as nested structs, are compared attribute by attribute so only the
+// nested attributes that changed are written
+func diffItems(update expression.UpdateBuilder, path string, old, current map[string]types.AttributeValue, skip ...string) (expression.UpdateBuilder, int) {
+	skipped := map[string]bool{}
+	for _, name := range skip {
+		skipped[name] = true
+	}
+
+	changes := 0
+	for _, name := range sortedNames(current) {
+		value := current[name]
+		previous, ok := old[name]
+		if skipped[name] || ok && reflect.DeepEqual(previous, value) {
+			continue
+		}
+
+		previousMap, wasMap := previous.(*types.AttributeValueMemberM)
+		currentMap, isMap := value.(*types.AttributeValueMemberM)
+		if wasMap && isMap && plainName(name) && plainNames(previousMap.Value) && plainNames(currentMap.Value) {
+			var nested int
+			update, nested = diffItems(update, joinPath(path, name), previousMap.Value, currentMap.Value)
+			changes += nested
+			continue
+		}
+		update = update.Set(attributePath(path, name), expression.Value(value))
+		changes++
+	}
+
+	for _, name := range sortedNames(old) {
+		if _, ok := current[name]; ok || skipped[name] {
+			continue
+		}
+		update = update.Remove(attributePath(path, name))
+		changes++
+	}
+	return update, changes
+}
+
+// attributePath names the attribute called name in the map at path, which is empty at the top level
+// Top level names are used as is, nested paths are only built from names plainName accepts
+func attributePath(path, name string) expression.NameBuilder {
+	if path == "" {
+		return expression.NameNoDotSplit(name)
+	}
+	return expression.Name(joinPath(path, name))
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// plainName reports whether name can be part of a dotted document path
+func plainName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, ".[]")
+}
+
+func plainNames(item map[string]types.AttributeValue) bool {
+	for name := range item {
+		if !plainName(name) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedNames(item map[string]types.AttributeValue) []string {
+	names := make([]string, 0, len(item))
+	for name := range item {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,249 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type TestTrackedDog struct {
+	Model
+	Name    string
+	Breed   string `mm:"index=breed-index"`
+	Home    TestHome
+	Tags    []string
+	Version int64 `mm:"version"`
+}
+
+// storedItem marshals v as DynamoDB would return it
+func storedItem(t *testing.T, v interface{}) map[string]types.AttributeValue {
+	item, err := attributevalue.MarshalMap(v)
+	require.NoError(t, err)
+	return item
+}
+
+func TestOperator_SaveWithDirtyTracking(t *testing.T) {
+	stored := TestTrackedDog{
+		Model:   Model{ID: "1", Type: "test_tracked_dog"},
+		Name:    "Buddy",
+		Breed:   "Lab",
+		Home:    TestHome{Address: &TestAddress{City: "Austin"}},
+		Tags:    []string{"good"},
+		Version: 2,
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(*TestTrackedDog)
+		setupMock func(*mocks.DynamoDBAPI)
+		expectErr error
+	}{
+		{
+			name:      "unchanged_is_a_no_op",
+			modify:    func(d *TestTrackedDog) {},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+		},
+		{
+			name:   "sets_changed_attributes",
+			modify: func(d *TestTrackedDog) { d.Name = "Rex" },
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					names := updatedNames(in)
+					return len(names) == 2 && names["Name"] && names["Version"] &&
+						strings.Contains(aws.ToString(in.ConditionExpression), "attribute_exists")
+				}), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+			},
+		},
+		{
+			name:   "sets_nested_attributes",
+			modify: func(d *TestTrackedDog) { d.Home.Address.City = "Houston" },
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					names := updatedNames(in)
+					return len(names) == 4 && names["Home"] && names["Address"] && names["City"] && names["Version"] &&
+						len(in.ExpressionAttributeValues) == 3
+				}), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+			},
+		},
+		{
+			name:   "removes_empty_index_key",
+			modify: func(d *TestTrackedDog) { d.Breed = "" },
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return updatedNames(in)["Breed"] && strings.Contains(aws.ToString(in.UpdateExpression), "REMOVE")
+				}), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+			},
+		},
+		{
+			name:   "version_conflict",
+			modify: func(d *TestTrackedDog) { d.Tags = append(d.Tags, "loyal") },
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, &types.ConditionalCheckFailedException{Message: aws.String("conditional check failed")}).Once()
+			},
+			expectErr: ErrVersionConflict,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			mockDB.On("GetItem", mock.Anything, mock.Anything, mock.Anything).
+				Return(&dynamodb.GetItemOutput{Item: storedItem(t, stored)}, nil).Once()
+			tc.setupMock(mockDB)
+
+			op := NewMagicModelOperatorWithClient(mockDB, "test-table").WithDirtyTracking()
+			var dog TestTrackedDog
+			require.NoError(t, op.Find(&dog, "1").Err)
+
+			tc.modify(&dog)
+			err := op.Save(&dog).Err
+			if tc.expectErr != nil {
+				require.ErrorIs(t, err, tc.expectErr)
+				require.Equal(t, int64(2), dog.Version)
+				return
+			}
+			require.NoError(t, err)
+
+			// The snapshot now matches the saved item, so saving again writes nothing
+			require.NoError(t, op.Save(&dog).Err)
+		})
+	}
+}
+
+func TestOperator_DirtyTrackingScope(t *testing.T) {
+	stored := TestDog{Model: Model{ID: "1", Type: "test_dog"}, Name: "Buddy", Breed: "Lab", Age: 3}
+
+	t.Run("tracks_items_loaded_by_all", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("Query", mock.Anything, mock.Anything, mock.Anything).
+			Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{storedItem(t, stored)}}, nil).Once()
+		mockDB.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+			names := updatedNames(in)
+			return len(names) == 1 && names["Age"]
+		}), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+		op := NewMagicModelOperatorWithClient(mockDB, "test-table").WithDirtyTracking()
+		var dogs []TestDog
+		require.NoError(t, op.All(&dogs).Err)
+
+		dogs[0].Age = 4
+		require.NoError(t, op.Save(&dogs[0]).Err)
+	})
+
+	t.Run("saves_untracked_items_whole", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("PutItem", mock.Anything, mock.Anything, mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+		dog := stored
+		require.NoError(t, NewMagicModelOperatorWithClient(mockDB, "test-table").WithDirtyTracking().Save(&dog).Err)
+	})
+
+	t.Run("off_by_default", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("GetItem", mock.Anything, mock.Anything, mock.Anything).
+			Return(&dynamodb.GetItemOutput{Item: storedItem(t, stored)}, nil).Once()
+		mockDB.On("PutItem", mock.Anything, mock.Anything, mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+		op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+		var dog TestDog
+		require.NoError(t, op.Find(&dog, "1").Err)
+		require.NoError(t, op.Save(&dog).Err)
+	})
+}
+
+func TestOperator_DirtyTrackingAfterWrites(t *testing.T) {
+	stored := TestTrackedDog{Model: Model{ID: "1", Type: "test_tracked_dog"}, Name: "Buddy", Breed: "Lab", Version: 2}
+
+	tests := []struct {
+		name      string
+		write     func(*Operator, *TestTrackedDog) error
+		setupMock func(*mocks.DynamoDBAPI)
+		saveMock  func(*mocks.DynamoDBAPI)
+	}{
+		{
+			name:  "update_then_unchanged_save",
+			write: func(op *Operator, d *TestTrackedDog) error { return op.Update(d, "Breed", "Poodle").Err },
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+			},
+			saveMock: func(dbMock *mocks.DynamoDBAPI) {},
+		},
+		{
+			name: "update_keeps_pending_changes",
+			write: func(op *Operator, d *TestTrackedDog) error {
+				d.Name = "Rex"
+				return op.Update(d, "Breed", "Poodle").Err
+			},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return updatedNames(in)["Breed"]
+				}), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+			},
+			saveMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					names := updatedNames(in)
+					return len(names) == 2 && names["Name"] && names["Version"] && updateValues(in)["Version"].(*types.AttributeValueMemberN).Value == "4"
+				}), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+			},
+		},
+		{
+			name: "transaction_then_unchanged_save",
+			write: func(op *Operator, d *TestTrackedDog) error {
+				return op.Transaction(func(tx *Tx) error {
+					return tx.UpdateFields(d, map[string]interface{}{"Breed": "Poodle", "Tags": []string{"good"}}).Err
+				}).Err
+			},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("TransactWriteItems", mock.Anything, mock.Anything, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+			},
+			saveMock: func(dbMock *mocks.DynamoDBAPI) {},
+		},
+		{
+			name: "delete_many_forgets_the_item",
+			write: func(op *Operator, d *TestTrackedDog) error {
+				return op.DeleteMany(&[]*TestTrackedDog{d}).Err
+			},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("BatchWriteItem", mock.Anything, mock.Anything, mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, nil).Once()
+			},
+			saveMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("PutItem", mock.Anything, mock.Anything, mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			mockDB.On("GetItem", mock.Anything, mock.Anything, mock.Anything).
+				Return(&dynamodb.GetItemOutput{Item: storedItem(t, stored)}, nil).Once()
+			tc.setupMock(mockDB)
+
+			op := NewMagicModelOperatorWithClient(mockDB, "test-table").WithDirtyTracking()
+			var dog TestTrackedDog
+			require.NoError(t, op.Find(&dog, "1").Err)
+			require.NoError(t, tc.write(op, &dog))
+
+			tc.saveMock(mockDB)
+			require.NoError(t, op.Save(&dog).Err)
+		})
+	}
+
+	t.Run("save_many_then_unchanged_save", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("BatchWriteItem", mock.Anything, mock.Anything, mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, nil).Once()
+
+		op := NewMagicModelOperatorWithClient(mockDB, "test-table").WithDirtyTracking()
+		dogs := []TestDog{{Name: "Buddy"}, {Name: "Rex"}}
+		require.NoError(t, op.CreateMany(&dogs).Err)
+		require.NoError(t, op.Save(&dogs[1]).Err)
+	})
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"github.com/aws/smithy-go"
+	"reflect"
+)
+
+var (
+	// ErrNotFound is returned when Find cannot find an item with the given ID
+	ErrNotFound = errors.New("item not found")
+
+	// ErrConditionFailed is returned when DynamoDB rejects a write because its condition did not hold
+	// ErrAlreadyExists and ErrVersionConflict are more specific forms of it
+	ErrConditionFailed = errors.New("condition check failed")
+
+	// ErrAlreadyExists is returned when Create finds an item with the same ID already stored
+	ErrAlreadyExists = mark(errors.New("item already exists"), ErrConditionFailed)
+
+	// ErrVersionConflict is returned when a versioned model was changed by someone else since it was loaded
+	// Reload the item and retry the write
+	ErrVersionConflict = mark(errors.New("version conflict: the item was modified since it was loaded"), ErrConditionFailed)
+
+	// ErrThrottled is returned when DynamoDB kept rejecting a request for exceeding the table's
+	// throughput or the account's request limits after the client's retries
+	ErrThrottled = errors.New("request throttled by DynamoDB")
+
+	// ErrValidation is returned when a model or an argument is rejected before anything is sent to DynamoDB
+	ErrValidation = errors.New("validation failed")
+)
+
+// throttlingCodes are the DynamoDB error codes that mean a request was throttled
+var throttlingCodes = map[string]bool{
+	"ProvisionedThroughputExceededException": true,
+	"RequestLimitExceeded":                   true,
+	"ThrottlingException":                    true,
+}
+
+// OperationError describes a failed operation on a model
+// Unwrap it with errors.Is or errors.As to reach the sentinel or AWS error that caused it
+type OperationError struct {
+	// Op is the operation that failed, e.g. "Find"
+	Op string
+	// Type is the model Type the operation ran on
+	Type string
+	// ID is the ID of the item, empty for queries
+	ID  string
+	Err error
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("encountered an error during %s operation: %v", e.Op, e.Err)
+}
+
+func (e *OperationError) Unwrap() error {
+	return e.Err
+}
+
+// newOperationError wraps err as a failure of op on q, a model or a slice of models, or nil when
+// the operation spans several models
+// DynamoDB's condition and throttling errors are marked with ErrConditionFailed and ErrThrottled
+func newOperationError(op string, q interface{}, err error) error {
+	opErr := &OperationError{Op: op, Err: classify(err)}
+	if q == nil {
+		return opErr
+	}
+	if name, err := ParseModelName(q); err == nil {
+		opErr.Type = name
+	}
+
+	v := reflect.ValueOf(q)
+	if v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		if id := v.Elem().FieldByName("ID"); id.IsValid() && id.Kind() == reflect.String {
+			opErr.ID = id.String()
+		}
+	}
+	return opErr
+}
+
+// markedError gives err the identity of a sentinel without changing its message,
+// so errors.Is matches the sentinel as well as anything err wraps
+type markedError struct {
+	err      error
+	sentinel error
+}
+
+func (e *markedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *markedError) Unwrap() []error {
+	return []error{e.err, e.sentinel}
+}
+
+func mark(err, sentinel error) error {
+	return &markedError{err: err, sentinel: sentinel}
+}
+
+// validationErrorf formats an error that matches ErrValidation
+func validationErrorf(format string, args ...interface{}) error {
+	return mark(fmt.Errorf(format, args...), ErrValidation)
+}
+
+// classify marks DynamoDB errors with the sentinel that describes them
+func classify(err error) error {
+	switch {
+	case errors.Is(err, ErrConditionFailed), errors.Is(err, ErrThrottled):
+		return err
+	case isConditionFailed(err):
+		return mark(err, ErrConditionFailed)
+	case isThrottled(err):
+		return mark(err, ErrThrottled)
+	}
+	return err
+}
+
+// isThrottled reports whether err is DynamoDB rejecting a request for exceeding throughput or request limits
+func isThrottled(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && throttlingCodes[apiErr.ErrorCode()]
+}
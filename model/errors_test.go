@@ -0,0 +1,145 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperator_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupMock func(*mocks.DynamoDBAPI)
+		operation func(*Operator) error
+		is        []error
+		expectOp  *OperationError
+	}{
+		{
+			name: "find_not_found",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("GetItem", mock.Anything, mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil)
+			},
+			operation: func(op *Operator) error {
+				return op.Find(&TestUser{}, "1").Err
+			},
+			is:       []error{ErrNotFound},
+			expectOp: &OperationError{Op: "Find", Type: "test_user"},
+		},
+		{
+			name: "find_throttled",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("GetItem", mock.Anything, mock.Anything, mock.Anything).Return(nil, &types.ProvisionedThroughputExceededException{})
+			},
+			operation: func(op *Operator) error {
+				return op.Find(&TestUser{}, "1").Err
+			},
+			is:       []error{ErrThrottled},
+			expectOp: &OperationError{Op: "Find", Type: "test_user"},
+		},
+		{
+			name: "create_already_exists",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("PutItem", mock.Anything, mock.Anything, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{})
+			},
+			operation: func(op *Operator) error {
+				return op.Create(&TestUser{}, WithID("taken")).Err
+			},
+			is:       []error{ErrAlreadyExists, ErrConditionFailed},
+			expectOp: &OperationError{Op: "Create", Type: "test_user", ID: "taken"},
+		},
+		{
+			name: "save_version_conflict",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("PutItem", mock.Anything, mock.Anything, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{})
+			},
+			operation: func(op *Operator) error {
+				return op.Save(&TestVersionedUser{Model: Model{ID: "1", Type: "test_versioned_user"}, Version: 2}).Err
+			},
+			is:       []error{ErrVersionConflict, ErrConditionFailed},
+			expectOp: &OperationError{Op: "Save", Type: "test_versioned_user", ID: "1"},
+		},
+		{
+			name: "delete_request_limit",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("DeleteItem", mock.Anything, mock.Anything, mock.Anything).Return(nil, &types.RequestLimitExceeded{})
+			},
+			operation: func(op *Operator) error {
+				return op.Delete(&TestUser{Model: Model{ID: "1", Type: "test_user"}}).Err
+			},
+			is:       []error{ErrThrottled},
+			expectOp: &OperationError{Op: "Delete", Type: "test_user", ID: "1"},
+		},
+		{
+			name:      "find_invalid_input",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			operation: func(op *Operator) error {
+				return op.Find(TestUser{}, "1").Err
+			},
+			is: []error{ErrValidation},
+		},
+		{
+			name:      "query_invalid_order",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			operation: func(op *Operator) error {
+				return op.Query(&[]TestUser{}).OrderBy("Name", Asc).Exec(op.Context())
+			},
+			is:       []error{ErrValidation},
+			expectOp: &OperationError{Op: "Query", Type: "test_user"},
+		},
+		{
+			name: "transaction_item_throttled",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("TransactWriteItems", mock.Anything, mock.Anything, mock.Anything).Return(nil, &types.TransactionCanceledException{
+					CancellationReasons: []types.CancellationReason{{Code: aws.String("ThrottlingError")}},
+				})
+			},
+			operation: func(op *Operator) error {
+				return op.Transaction(func(tx *Tx) error {
+					tx.Save(&TestUser{Model: Model{ID: "1", Type: "test_user"}})
+					return nil
+				}).Err
+			},
+			is: []error{ErrThrottled},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			tc.setupMock(mockDB)
+
+			err := tc.operation(NewMagicModelOperatorWithClient(mockDB, "test-table"))
+			require.Error(t, err)
+			for _, target := range tc.is {
+				require.ErrorIs(t, err, target)
+			}
+
+			if tc.expectOp != nil {
+				var opErr *OperationError
+				require.True(t, errors.As(err, &opErr))
+				require.Equal(t, tc.expectOp.Op, opErr.Op)
+				require.Equal(t, tc.expectOp.Type, opErr.Type)
+				require.Equal(t, tc.expectOp.ID, opErr.ID)
+			}
+		})
+	}
+}
+
+func TestOperator_Errors_KeepAWSError(t *testing.T) {
+	mockDB := mocks.NewDynamoDBAPI(t)
+	mockDB.On("PutItem", mock.Anything, mock.Anything, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{Message: aws.String("failed")})
+
+	err := NewMagicModelOperatorWithClient(mockDB, "test-table").Save(&TestUser{}).Err
+	require.ErrorIs(t, err, ErrConditionFailed)
+	require.NotErrorIs(t, err, ErrVersionConflict)
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	require.True(t, errors.As(err, &conditionFailed))
+	require.Equal(t, "failed", aws.ToString(conditionFailed.Message))
+}
@@ -1,10 +1,9 @@
 package model
 
 import (
-	"context"
-	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
@@ -30,24 +29,47 @@ func (o *Operator) Find(q interface{}, id string) *Operator {
 		"ID":   &types.AttributeValueMemberS{Value: id},
 	}
 
-	out, err := svc.GetItem(context.TODO(), &dynamodb.GetItemInput{
-		TableName: aws.String(dynamoDBTableName),
+	input := &dynamodb.GetItemInput{
+		TableName: aws.String(o.tableName),
 		Key:       payload,
-	})
+	}
+	if len(o.projection) > 0 {
+		meta, err := metaOf(q)
+		if err != nil {
+			o.Err = newOperationError("Find", q, err)
+			return o
+		}
+		expr, err := withProjection(expression.NewBuilder(), meta.fields, o.projection).Build()
+		if err != nil {
+			o.Err = newOperationError("Find", q, err)
+			return o
+		}
+		input.ProjectionExpression = expr.Projection()
+		input.ExpressionAttributeNames = expr.Names()
+	}
+
+	out, err := o.db.GetItem(o.Context(), input)
 
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during Find operation: %v", err)
+		o.Err = newOperationError("Find", q, err)
 		return o
 	}
 
 	if out.Item == nil {
-		o.Err = fmt.Errorf("encountered an error during Find operation: item not found")
+		o.Err = newOperationError("Find", q, ErrNotFound)
 		return o
 	}
 
 	err = attributevalue.UnmarshalMap(out.Item, q)
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during Find operation: %v", err)
+		o.Err = newOperationError("Find", q, err)
+		return o
+	}
+	o.track(q)
+
+	err = callHook(o.Context(), q, AfterFinder.AfterFind)
+	if err != nil {
+		o.Err = newOperationError("Find", q, err)
 		return o
 	}
 	return o
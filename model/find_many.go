@@ -0,0 +1,124 @@
+package model
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// maxBatchGetItems is the most keys DynamoDB accepts in a single BatchGetItem call
+const maxBatchGetItems = 100
+
+// FindMany loads the items with the given IDs into the slice q points to using BatchGetItem
+// Items are returned in the same order as ids. IDs that do not exist, or that were soft deleted
+// when excludeDeleted is true, are returned as missing instead of failing the call
+func (o *Operator) FindMany(q interface{}, ids []string, excludeDeleted bool) ([]string, error) {
+	if o.Err != nil {
+		return nil, o.Err
+	}
+
+	name, err := ParseModelName(q)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateInputSlice(q, "FindMany", name)
+	if err != nil {
+		return nil, err
+	}
+
+	// BatchGetItem rejects duplicate keys, so only request each ID once
+	var unique []string
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	found := make(map[string]map[string]types.AttributeValue, len(unique))
+	for start := 0; start < len(unique); start += maxBatchGetItems {
+		end := min(start+maxBatchGetItems, len(unique))
+		err = o.getChunk(name, unique[start:end], found)
+		if err != nil {
+			return nil, newOperationError("FindMany", q, err)
+		}
+	}
+
+	items := make([]map[string]types.AttributeValue, 0, len(ids))
+	var missing []string
+	reported := make(map[string]bool)
+	for _, id := range ids {
+		item, ok := found[id]
+		if ok && excludeDeleted && isSoftDeleted(item) {
+			ok = false
+		}
+
+		if ok {
+			items = append(items, item)
+		} else if !reported[id] {
+			reported[id] = true
+			missing = append(missing, id)
+		}
+	}
+
+	err = attributevalue.UnmarshalListOfMaps(items, q)
+	if err != nil {
+		return nil, newOperationError("FindMany", q, err)
+	}
+	o.track(q)
+
+	return missing, nil
+}
+
+// getChunk sends a single BatchGetItem call, resending unprocessed keys with exponential backoff,
+// and stores every item it reads in found by ID
+func (o *Operator) getChunk(typeName string, ids []string, found map[string]map[string]types.AttributeValue) error {
+	keys := make([]map[string]types.AttributeValue, len(ids))
+	for i, id := range ids {
+		keys[i] = map[string]types.AttributeValue{
+			"Type": &types.AttributeValueMemberS{Value: typeName},
+			"ID":   &types.AttributeValueMemberS{Value: id},
+		}
+	}
+
+	request := map[string]types.KeysAndAttributes{o.tableName: {Keys: keys}}
+	delay := batchRetryDelay
+	for attempt := 0; ; attempt++ {
+		out, err := o.db.BatchGetItem(o.Context(), &dynamodb.BatchGetItemInput{RequestItems: request})
+		if err != nil {
+			return err
+		}
+
+		for _, item := range out.Responses[o.tableName] {
+			if id, ok := item["ID"].(*types.AttributeValueMemberS); ok {
+				found[id.Value] = item
+			}
+		}
+
+		request = out.UnprocessedKeys
+		if len(request[o.tableName].Keys) == 0 {
+			return nil
+		}
+		if attempt >= batchMaxRetries {
+			return mark(fmt.Errorf("%d keys were not processed by DynamoDB after retrying", len(request[o.tableName].Keys)), ErrThrottled)
+		}
+
+		if err := o.sleep(delay); err != nil {
+			return err
+		}
+		delay *= 2
+	}
+}
+
+// isSoftDeleted reports whether an item has a non-null DeletedAt attribute
+func isSoftDeleted(item map[string]types.AttributeValue) bool {
+	deletedAt, ok := item["DeletedAt"]
+	if !ok {
+		return false
+	}
+	null, isNull := deletedAt.(*types.AttributeValueMemberNULL)
+	return !isNull || !null.Value
+}
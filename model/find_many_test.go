@@ -0,0 +1,140 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// batchGetResponder answers BatchGetItem calls with every requested key found in items,
+// leaving the first unprocessed times requests' last key unprocessed
+func batchGetResponder(items map[string]*TestUser, unprocessed int) func(context.Context, *dynamodb.BatchGetItemInput, ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+	return func(_ context.Context, in *dynamodb.BatchGetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+		keys := in.RequestItems["test-table"].Keys
+		out := &dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{}}
+		if unprocessed > 0 {
+			unprocessed--
+			out.UnprocessedKeys = map[string]types.KeysAndAttributes{"test-table": {Keys: keys[len(keys)-1:]}}
+			keys = keys[:len(keys)-1]
+		}
+		for _, key := range keys {
+			id := key["ID"].(*types.AttributeValueMemberS).Value
+			if user, ok := items[id]; ok {
+				item, _ := attributevalue.MarshalMap(user)
+				out.Responses["test-table"] = append(out.Responses["test-table"], item)
+			}
+		}
+		return out, nil
+	}
+}
+
+func TestOperator_FindMany(t *testing.T) {
+	delay := batchRetryDelay
+	batchRetryDelay = 0
+	t.Cleanup(func() { batchRetryDelay = delay })
+
+	deletedAt := time.Now()
+	stored := map[string]*TestUser{}
+	for i := 1; i <= 150; i++ {
+		id := fmt.Sprint(i)
+		stored[id] = &TestUser{Model: Model{ID: id, Type: "test_user"}, Name: "user-" + id}
+	}
+	stored["7"].DeletedAt = &deletedAt
+
+	tests := []struct {
+		name            string
+		setupMock       func(*mocks.DynamoDBAPI)
+		ids             []string
+		excludeDeleted  bool
+		expectError     bool
+		errorContains   string
+		expectedIDs     []string
+		expectedMissing []string
+	}{
+		{
+			name: "preserves_order_and_reports_missing",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("BatchGetItem", mock.Anything, mock.Anything, mock.Anything).Return(batchGetResponder(stored, 0)).Once()
+			},
+			ids:             []string{"3", "404", "1", "3", "7"},
+			expectedIDs:     []string{"3", "1", "3", "7"},
+			expectedMissing: []string{"404"},
+		},
+		{
+			name: "excludes_soft_deleted",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("BatchGetItem", mock.Anything, mock.Anything, mock.Anything).Return(batchGetResponder(stored, 0)).Once()
+			},
+			ids:             []string{"7", "8"},
+			excludeDeleted:  true,
+			expectedIDs:     []string{"8"},
+			expectedMissing: []string{"7"},
+		},
+		{
+			name: "chunks_and_retries_unprocessed_keys",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				responder := batchGetResponder(stored, 1)
+				dbMock.On("BatchGetItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.BatchGetItemInput) bool {
+					return len(in.RequestItems["test-table"].Keys) <= maxBatchGetItems
+				}), mock.Anything).Return(responder).Times(3)
+			},
+			ids: func() []string {
+				ids := make([]string, 150)
+				for i := range ids {
+					ids[i] = fmt.Sprint(i + 1)
+				}
+				return ids
+			}(),
+		},
+		{
+			name: "request_fails",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("BatchGetItem", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("dynamodb error"))
+			},
+			ids:           []string{"1"},
+			expectError:   true,
+			errorContains: "dynamodb error",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			if tc.setupMock != nil {
+				tc.setupMock(mockDB)
+			}
+
+			op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+			var users []TestUser
+			missing, err := op.FindMany(&users, tc.ids, tc.excludeDeleted)
+
+			if tc.expectError {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.errorContains)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedMissing, missing)
+
+			expectedIDs := tc.expectedIDs
+			if expectedIDs == nil {
+				expectedIDs = tc.ids
+			}
+			ids := make([]string, len(users))
+			for i, user := range users {
+				ids[i] = user.ID
+			}
+			require.Equal(t, expectedIDs, ids)
+		})
+	}
+}
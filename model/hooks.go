@@ -0,0 +1,77 @@
+package model
+
+import "context"
+
+// Models can implement any of the hook interfaces below to run code around their operations,
+// e.g. to normalize fields or keep derived fields up to date
+// Before hooks run once the model has been validated and its ID and timestamps assigned, and an
+// error aborts the operation before anything is sent to DynamoDB. After hooks run once the write
+// succeeded, so an error from them is returned but the write is not undone
+// Hooks run for the single item operations as well as in batches and transactions
+
+// BeforeCreator is called by Create before the item is written
+type BeforeCreator interface {
+	BeforeCreate(ctx context.Context) error
+}
+
+// AfterCreator is called by Create after the item was written
+type AfterCreator interface {
+	AfterCreate(ctx context.Context) error
+}
+
+// BeforeSaver is called by Save before the item is written
+type BeforeSaver interface {
+	BeforeSave(ctx context.Context) error
+}
+
+// AfterSaver is called by Save after the item was written
+type AfterSaver interface {
+	AfterSave(ctx context.Context) error
+}
+
+// BeforeUpdater is called by Update once the new value has been set on the model, and may still
+// change it before the update is built
+type BeforeUpdater interface {
+	BeforeUpdate(ctx context.Context) error
+}
+
+// AfterUpdater is called by Update after the item was updated
+type AfterUpdater interface {
+	AfterUpdate(ctx context.Context) error
+}
+
+// BeforeDeleter is called by Delete and SoftDelete before the item is deleted
+type BeforeDeleter interface {
+	BeforeDelete(ctx context.Context) error
+}
+
+// AfterDeleter is called by Delete and SoftDelete after the item was deleted
+type AfterDeleter interface {
+	AfterDelete(ctx context.Context) error
+}
+
+// AfterFinder is called by Find after the item was loaded
+type AfterFinder interface {
+	AfterFind(ctx context.Context) error
+}
+
+// callHook calls hook on q if q implements the hook's interface H
+// e.g. callHook(ctx, q, BeforeCreator.BeforeCreate)
+func callHook[H any](ctx context.Context, q interface{}, hook func(H, context.Context) error) error {
+	if h, ok := q.(H); ok {
+		return hook(h, ctx)
+	}
+	return nil
+}
+
+// afterHook returns a function that calls hook on q once its write succeeded, or nil when
+// q does not implement the hook
+func afterHook[H any](q interface{}, hook func(H, context.Context) error) func(context.Context) error {
+	h, ok := q.(H)
+	if !ok {
+		return nil
+	}
+	return func(ctx context.Context) error {
+		return hook(h, ctx)
+	}
+}
@@ -0,0 +1,225 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type hookCtxKey struct{}
+
+// TestHookedDog normalizes its name and keeps a derived search key in its hooks
+// Calls records every hook that ran along with the context value it was given
+type TestHookedDog struct {
+	Model
+	Name      string
+	SearchKey string
+	Calls     []string `dynamodbav:"-"`
+	Fail      string   `dynamodbav:"-"`
+}
+
+func (d *TestHookedDog) record(ctx context.Context, hook string) error {
+	value, _ := ctx.Value(hookCtxKey{}).(string)
+	d.Calls = append(d.Calls, hook+":"+value)
+	if d.Fail == hook {
+		return errors.New(hook + " failed")
+	}
+	return nil
+}
+
+func (d *TestHookedDog) BeforeCreate(ctx context.Context) error {
+	d.Name = strings.TrimSpace(d.Name)
+	d.SearchKey = strings.ToLower(d.Name)
+	return d.record(ctx, "BeforeCreate")
+}
+
+func (d *TestHookedDog) AfterCreate(ctx context.Context) error { return d.record(ctx, "AfterCreate") }
+
+func (d *TestHookedDog) BeforeSave(ctx context.Context) error {
+	d.SearchKey = strings.ToLower(d.Name)
+	return d.record(ctx, "BeforeSave")
+}
+
+func (d *TestHookedDog) AfterSave(ctx context.Context) error { return d.record(ctx, "AfterSave") }
+
+func (d *TestHookedDog) BeforeUpdate(ctx context.Context) error {
+	d.Name = strings.TrimSpace(d.Name)
+	return d.record(ctx, "BeforeUpdate")
+}
+
+func (d *TestHookedDog) AfterUpdate(ctx context.Context) error { return d.record(ctx, "AfterUpdate") }
+
+func (d *TestHookedDog) BeforeDelete(ctx context.Context) error { return d.record(ctx, "BeforeDelete") }
+
+func (d *TestHookedDog) AfterDelete(ctx context.Context) error { return d.record(ctx, "AfterDelete") }
+
+func (d *TestHookedDog) AfterFind(ctx context.Context) error { return d.record(ctx, "AfterFind") }
+
+func TestOperator_Hooks(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hookCtxKey{}, "req")
+	stored := func() *TestHookedDog {
+		return &TestHookedDog{Model: Model{ID: "1", Type: "test_hooked_dog"}, Name: "Buddy"}
+	}
+
+	tests := []struct {
+		name        string
+		dog         *TestHookedDog
+		fail        string
+		setupMock   func(*mocks.DynamoDBAPI)
+		operation   func(*Operator, *TestHookedDog) *Operator
+		expectCalls []string
+		expectErr   string
+		verify      func(*testing.T, *TestHookedDog)
+	}{
+		{
+			name: "create",
+			dog:  &TestHookedDog{Name: "  Buddy "},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("PutItem", ctx, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+					key, ok := in.Item["SearchKey"].(*types.AttributeValueMemberS)
+					return ok && key.Value == "buddy"
+				}), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil)
+			},
+			operation: func(op *Operator, dog *TestHookedDog) *Operator {
+				return op.Create(dog)
+			},
+			expectCalls: []string{"BeforeCreate:req", "AfterCreate:req"},
+			verify: func(t *testing.T, dog *TestHookedDog) {
+				require.Equal(t, "Buddy", dog.Name)
+				require.NotEmpty(t, dog.ID)
+			},
+		},
+		{
+			name:      "before_create_aborts",
+			dog:       &TestHookedDog{Name: "Buddy"},
+			fail:      "BeforeCreate",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			operation: func(op *Operator, dog *TestHookedDog) *Operator {
+				return op.Create(dog)
+			},
+			expectCalls: []string{"BeforeCreate:req"},
+			expectErr:   "encountered an error during Create operation: BeforeCreate failed",
+		},
+		{
+			name: "after_create_error",
+			dog:  &TestHookedDog{Name: "Buddy"},
+			fail: "AfterCreate",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("PutItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.PutItemOutput{}, nil)
+			},
+			operation: func(op *Operator, dog *TestHookedDog) *Operator {
+				return op.Create(dog)
+			},
+			expectCalls: []string{"BeforeCreate:req", "AfterCreate:req"},
+			expectErr:   "AfterCreate failed",
+		},
+		{
+			name: "save",
+			dog:  stored(),
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("PutItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.PutItemOutput{}, nil)
+			},
+			operation: func(op *Operator, dog *TestHookedDog) *Operator {
+				return op.Save(dog)
+			},
+			expectCalls: []string{"BeforeSave:req", "AfterSave:req"},
+		},
+		{
+			name: "update_uses_normalized_value",
+			dog:  stored(),
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", ctx, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					for _, v := range in.ExpressionAttributeValues {
+						if s, ok := v.(*types.AttributeValueMemberS); ok && s.Value == "Rex" {
+							return true
+						}
+					}
+					return false
+				}), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil)
+			},
+			operation: func(op *Operator, dog *TestHookedDog) *Operator {
+				return op.Update(dog, "Name", " Rex ")
+			},
+			expectCalls: []string{"BeforeUpdate:req", "AfterUpdate:req"},
+		},
+		{
+			name:      "before_delete_aborts",
+			dog:       stored(),
+			fail:      "BeforeDelete",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			operation: func(op *Operator, dog *TestHookedDog) *Operator {
+				return op.Delete(dog)
+			},
+			expectCalls: []string{"BeforeDelete:req"},
+			expectErr:   "BeforeDelete failed",
+		},
+		{
+			name: "soft_delete",
+			dog:  stored(),
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil)
+			},
+			operation: func(op *Operator, dog *TestHookedDog) *Operator {
+				return op.SoftDelete(dog)
+			},
+			expectCalls: []string{"BeforeDelete:req", "AfterDelete:req"},
+		},
+		{
+			name: "find",
+			dog:  &TestHookedDog{},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("GetItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+					"ID":   &types.AttributeValueMemberS{Value: "1"},
+					"Name": &types.AttributeValueMemberS{Value: "Buddy"},
+				}}, nil)
+			},
+			operation: func(op *Operator, dog *TestHookedDog) *Operator {
+				return op.Find(dog, "1")
+			},
+			expectCalls: []string{"AfterFind:req"},
+		},
+		{
+			name: "transaction",
+			dog:  stored(),
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("TransactWriteItems", ctx, mock.Anything, mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil)
+			},
+			operation: func(op *Operator, dog *TestHookedDog) *Operator {
+				return op.Transaction(func(tx *Tx) error {
+					tx.Save(dog)
+					return nil
+				})
+			},
+			expectCalls: []string{"BeforeSave:req", "AfterSave:req"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			tc.setupMock(mockDB)
+			tc.dog.Fail = tc.fail
+
+			op := NewMagicModelOperatorWithClient(mockDB, "test-table").WithContext(ctx)
+			result := tc.operation(op, tc.dog)
+
+			if tc.expectErr != "" {
+				require.Error(t, result.Err)
+				require.Contains(t, result.Err.Error(), tc.expectErr)
+			} else {
+				require.NoError(t, result.Err)
+			}
+			require.Equal(t, tc.expectCalls, tc.dog.Calls)
+			if tc.verify != nil {
+				tc.verify(t, tc.dog)
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"time"
+)
+
+// indexPollInterval and indexWaitTimeout control how long table setup waits for a new index to be built
+// They are variables so tests can shorten them
+var (
+	indexPollInterval = 5 * time.Second
+	indexWaitTimeout  = 10 * time.Minute
+)
+
+// The local secondary indexes provisioned by TableOptions.TimeIndexes
+const (
+	createdAtIndex = "created-at-index"
+	updatedAtIndex = "updated-at-index"
+)
+
+// timestampLayout is how CreatedAt and UpdatedAt are stored: RFC 3339 in UTC with all nine fractional
+// digits. RFC3339Nano drops trailing zeros, so .5Z would sort after .55Z, while this layout gives every
+// value the same width and has the time indexes sort them chronologically. It still parses as RFC 3339
+const timestampLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// timestamp returns the time to store in CreatedAt and UpdatedAt
+func timestamp() time.Time {
+	return time.Now().UTC()
+}
+
+// isTimestamp reports whether attribute is stored with timestampLayout
+func isTimestamp(attribute string) bool {
+	return attribute == "CreatedAt" || attribute == "UpdatedAt"
+}
+
+// timestampValue returns v as it is stored in a timestamp attribute when it is a time.Time
+func timestampValue(v interface{}) interface{} {
+	if t, ok := v.(time.Time); ok {
+		return t.UTC().Format(timestampLayout)
+	}
+	return v
+}
+
+// indexMeta is a global secondary index declared on a model field with mm:"index=name"
+// The index is keyed by Type and the field, so each model only ever reads its own items
+type indexMeta struct {
+	name      string
+	field     string
+	attribute string
+	attrType  types.ScalarAttributeType
+}
+
+// newIndexMeta validates that field can be an index key and describes the index
+func newIndexMeta(t reflect.Type, field reflect.StructField, name string) (indexMeta, error) {
+	if name == "" {
+		return indexMeta{}, validationErrorf("index tag on %s.%s must name the index, e.g. %s:\"index=name\"", t.Name(), field.Name, tagName)
+	}
+
+	index := indexMeta{name: name, field: field.Name, attribute: attributeName(field)}
+	switch {
+	case field.Type.Kind() == reflect.String:
+		index.attrType = types.ScalarAttributeTypeS
+	case isNumberKind(field.Type.Kind()):
+		index.attrType = types.ScalarAttributeTypeN
+	case field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Uint8:
+		index.attrType = types.ScalarAttributeTypeB
+	default:
+		return indexMeta{}, validationErrorf("index field %s.%s must be a string, number or []byte, got %s", t.Name(), field.Name, field.Type)
+	}
+	return index, nil
+}
+
+// accepts reports whether v can be compared against the index key, which DynamoDB requires to match its type
+// Empty strings and binary values are never accepted, as DynamoDB rejects them as key values and
+// marshalItem leaves them out of the index
+func (i indexMeta) accepts(v interface{}) bool {
+	if v == nil || isEmptyKey(reflect.ValueOf(v)) {
+		return false
+	}
+	t := reflect.TypeOf(v)
+	switch i.attrType {
+	case types.ScalarAttributeTypeS:
+		return t.Kind() == reflect.String
+	case types.ScalarAttributeTypeN:
+		return isNumberKind(t.Kind())
+	default:
+		return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+	}
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// indexFor returns the index that can answer a condition on fieldName with value v, if any, along with
+// the key condition on its sort key. v is either a value compared for equality or a Comparison
+// fieldName may be the Go name of the field or the attribute it is stored as
+func (m *modelMeta) indexFor(fieldName string, v interface{}) (*indexMeta, expression.KeyConditionBuilder) {
+	for i := range m.indexes {
+		index := &m.indexes[i]
+		if index.field != fieldName && index.attribute != fieldName {
+			continue
+		}
+
+		comparison, ok := v.(Comparison)
+		if !ok {
+			comparison = Eq(v)
+		}
+		if !allAccepted(*index, comparison.values) {
+			continue
+		}
+		if key, ok := comparison.keyCondition(index.attribute); ok {
+			return index, key
+		}
+	}
+	return nil, expression.KeyConditionBuilder{}
+}
+
+// isIndexed reports whether field is the key of one of the model's indexes
+func (m *modelMeta) isIndexed(field string) bool {
+	for _, index := range m.indexes {
+		if index.field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// allAccepted reports whether every value can be compared against the index key
+func allAccepted(index indexMeta, values []interface{}) bool {
+	for _, v := range values {
+		if !index.accepts(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// isEmptyKey reports whether v is an empty string or binary value, which DynamoDB rejects as an index key
+func isEmptyKey(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return v.Len() == 0
+	case reflect.Slice:
+		return v.Type().Elem().Kind() == reflect.Uint8 && v.Len() == 0
+	}
+	return false
+}
+
+// marshalItem marshals the model q points to, leaving out indexed attributes with empty values
+// so the item is simply absent from those indexes instead of being rejected by DynamoDB
+// CreatedAt and UpdatedAt are stored with timestampLayout
+func marshalItem(q interface{}) (map[string]types.AttributeValue, error) {
+	item, err := attributevalue.MarshalMap(q)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := reflect.ValueOf(q).Elem()
+	meta, err := metaFor(payload.Type())
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"CreatedAt", "UpdatedAt"} {
+		if t, ok := payload.FieldByName(name).Interface().(time.Time); ok {
+			item[name] = &types.AttributeValueMemberS{Value: timestampValue(t).(string)}
+		}
+	}
+	for _, index := range meta.indexes {
+		if isEmptyKey(payload.FieldByName(index.field)) {
+			delete(item, index.attribute)
+		}
+	}
+	return item, nil
+}
+
+// planWhereV4 builds the expression for conditions on the model q holds. The first condition on an
+// indexed field that DynamoDB can apply to a key, such as equality or Gt, becomes part of the key
+// condition so the query reads that index instead of the whole Type partition
+// It returns the index name, or nil when the base table should be queried
+func planWhereV4(q interface{}, typeName string, conditions []WhereV4Condition, projection ...string) (expression.Expression, *string, error) {
+	meta, err := metaOf(q)
+	if err != nil {
+		return expression.Expression{}, nil, err
+	}
+
+	keyCondition := expression.Key("Type").Equal(expression.Value(typeName))
+	for i, condition := range conditions {
+		if len(condition.FieldValues) != 1 {
+			continue
+		}
+		index, key := meta.indexFor(condition.FieldName, condition.FieldValues[0])
+		if index == nil {
+			continue
+		}
+
+		keyCondition = keyCondition.And(key)
+		rest := append(append([]WhereV4Condition{}, conditions[:i]...), conditions[i+1:]...)
+		expr, err := buildWhereV4KeyExpression(meta.fields, keyCondition, rest, projection...)
+		return expr, aws.String(index.name), err
+	}
+
+	expr, err := buildWhereV4KeyExpression(meta.fields, keyCondition, conditions, projection...)
+	return expr, nil, err
+}
+
+// tableIndexes collects the indexes declared by models, rejecting indexes that share a name
+// or attribute but disagree on their definition
+func tableIndexes(models []interface{}) ([]indexMeta, error) {
+	var indexes []indexMeta
+	byName := map[string]indexMeta{}
+	byAttribute := map[string]types.ScalarAttributeType{}
+	for _, m := range models {
+		meta, err := metaOf(m)
+		if err != nil {
+			return nil, err
+		}
+		for _, index := range meta.indexes {
+			if attrType, ok := byAttribute[index.attribute]; ok && attrType != index.attrType {
+				return nil, validationErrorf("attribute %s is indexed as both %s and %s", index.attribute, attrType, index.attrType)
+			}
+			byAttribute[index.attribute] = index.attrType
+
+			if existing, ok := byName[index.name]; ok {
+				if existing.attribute != index.attribute {
+					return nil, validationErrorf("index %s is declared on both %s and %s", index.name, existing.attribute, index.attribute)
+				}
+				continue
+			}
+			byName[index.name] = index
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes, nil
+}
+
+// definition returns the index as DynamoDB expects it when creating a table or index
+func (i indexMeta) definition() types.GlobalSecondaryIndex {
+	return types.GlobalSecondaryIndex{
+		IndexName: aws.String(i.name),
+		KeySchema: []types.KeySchemaElement{{
+			AttributeName: aws.String("Type"),
+			KeyType:       types.KeyTypeHash,
+		}, {
+			AttributeName: aws.String(i.attribute),
+			KeyType:       types.KeyTypeRange,
+		}},
+		Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+	}
+}
+
+// timeIndexDefinitions returns the local secondary indexes that sort each Type partition by CreatedAt and UpdatedAt
+func timeIndexDefinitions() []types.LocalSecondaryIndex {
+	var definitions []types.LocalSecondaryIndex
+	for _, index := range [][2]string{{createdAtIndex, "CreatedAt"}, {updatedAtIndex, "UpdatedAt"}} {
+		name, attribute := index[0], index[1]
+		definitions = append(definitions, types.LocalSecondaryIndex{
+			IndexName: aws.String(name),
+			KeySchema: []types.KeySchemaElement{{
+				AttributeName: aws.String("Type"),
+				KeyType:       types.KeyTypeHash,
+			}, {
+				AttributeName: aws.String(attribute),
+				KeyType:       types.KeyTypeRange,
+			}},
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		})
+	}
+	return definitions
+}
+
+// attributeDefinitions returns the key attributes of the base table along with those of indexes
+// and, when timeIndexes is set, of the CreatedAt and UpdatedAt local secondary indexes
+func attributeDefinitions(indexes []indexMeta, timeIndexes bool) []types.AttributeDefinition {
+	definitions := []types.AttributeDefinition{{
+		AttributeName: aws.String("Type"),
+		AttributeType: types.ScalarAttributeTypeS,
+	}, {
+		AttributeName: aws.String("ID"),
+		AttributeType: types.ScalarAttributeTypeS,
+	}}
+
+	if timeIndexes {
+		definitions = append(definitions, types.AttributeDefinition{
+			AttributeName: aws.String("CreatedAt"),
+			AttributeType: types.ScalarAttributeTypeS,
+		}, types.AttributeDefinition{
+			AttributeName: aws.String("UpdatedAt"),
+			AttributeType: types.ScalarAttributeTypeS,
+		})
+	}
+
+	defined := map[string]bool{"Type": true, "ID": true, "CreatedAt": timeIndexes, "UpdatedAt": timeIndexes}
+	for _, index := range indexes {
+		if defined[index.attribute] {
+			continue
+		}
+		defined[index.attribute] = true
+		definitions = append(definitions, types.AttributeDefinition{
+			AttributeName: aws.String(index.attribute),
+			AttributeType: index.attrType,
+		})
+	}
+	return definitions
+}
+
+// addMissingIndexes creates the global indexes an existing table does not have yet, one at a time
+// since DynamoDB only builds a single new index per UpdateTable call. Local indexes can only be
+// created along with the table, so missing time indexes are reported as an error
+func (o *Operator) addMissingIndexes(ctx context.Context, indexes []indexMeta, timeIndexes bool) error {
+	table, err := o.db.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(o.tableName)})
+	if err != nil {
+		return fmt.Errorf("encountered an error during init operation: %w", err)
+	}
+
+	if timeIndexes {
+		local := map[string]bool{}
+		for _, index := range table.Table.LocalSecondaryIndexes {
+			local[aws.ToString(index.IndexName)] = true
+		}
+		if !local[createdAtIndex] || !local[updatedAtIndex] {
+			return fmt.Errorf("encountered an error during init operation: table %s exists without the %s and %s local secondary indexes, which can only be added when the table is created", o.tableName, createdAtIndex, updatedAtIndex)
+		}
+	}
+
+	existing := map[string]bool{}
+	for _, index := range table.Table.GlobalSecondaryIndexes {
+		existing[aws.ToString(index.IndexName)] = true
+	}
+
+	for _, index := range indexes {
+		if existing[index.name] {
+			continue
+		}
+
+		definition := index.definition()
+		_, err = o.db.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+			TableName:            aws.String(o.tableName),
+			AttributeDefinitions: attributeDefinitions([]indexMeta{index}, false),
+			GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{{
+				Create: &types.CreateGlobalSecondaryIndexAction{
+					IndexName:  definition.IndexName,
+					KeySchema:  definition.KeySchema,
+					Projection: definition.Projection,
+				},
+			}},
+		})
+		if err != nil {
+			return fmt.Errorf("encountered an error while creating index %s: %w", index.name, err)
+		}
+
+		err = o.waitForIndex(ctx, index.name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForIndex polls the table until the named index has finished building
+func (o *Operator) waitForIndex(ctx context.Context, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, indexWaitTimeout)
+	defer cancel()
+
+	for {
+		table, err := o.db.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(o.tableName)})
+		if err != nil {
+			return fmt.Errorf("error while waiting for index %s to be created: %w", name, err)
+		}
+		for _, index := range table.Table.GlobalSecondaryIndexes {
+			if aws.ToString(index.IndexName) == name && index.IndexStatus == types.IndexStatusActive {
+				return nil
+			}
+		}
+
+		timer := time.NewTimer(indexPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("error while waiting for index %s to be created: %w", name, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
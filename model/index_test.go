@@ -0,0 +1,300 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDog is a model with global secondary indexes
+type TestDog struct {
+	Model
+	Name  string
+	Breed string `mm:"index=breed-index"`
+	Age   int    `mm:"index=age-index"`
+}
+
+func indexQuery(index string) interface{} {
+	return mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return aws.ToString(in.IndexName) == index
+	})
+}
+
+func TestOperator_IndexedQueries(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation func(*Operator, *[]TestDog) *Operator
+		index     string
+	}{
+		{
+			name: "where_uses_index",
+			operation: func(op *Operator, dogs *[]TestDog) *Operator {
+				return op.Where(dogs, "Breed", "Dalmatian")
+			},
+			index: "breed-index",
+		},
+		{
+			name: "where_without_index",
+			operation: func(op *Operator, dogs *[]TestDog) *Operator {
+				return op.Where(dogs, "Name", "Buddy")
+			},
+		},
+		{
+			name: "where_v4_uses_index",
+			operation: func(op *Operator, dogs *[]TestDog) *Operator {
+				return op.WhereV4(true, dogs, "Name", "Buddy").WhereV4(false, dogs, "Age", 3)
+			},
+			index: "age-index",
+		},
+		{
+			name: "where_v4_in_skips_index",
+			operation: func(op *Operator, dogs *[]TestDog) *Operator {
+				return op.WhereV4(false, dogs, "Breed", []string{"Dalmatian", "Labrador"})
+			},
+		},
+		{
+			name: "where_v4_mismatched_type_skips_index",
+			operation: func(op *Operator, dogs *[]TestDog) *Operator {
+				return op.WhereV4(false, dogs, "Age", "3")
+			},
+		},
+		{
+			name: "where_empty_value_skips_index",
+			operation: func(op *Operator, dogs *[]TestDog) *Operator {
+				return op.Where(dogs, "Breed", "")
+			},
+		},
+		{
+			name: "where_v4_empty_value_skips_index",
+			operation: func(op *Operator, dogs *[]TestDog) *Operator {
+				return op.WhereV4(false, dogs, "Breed", "")
+			},
+		},
+		{
+			name: "query_empty_value_skips_index",
+			operation: func(op *Operator, dogs *[]TestDog) *Operator {
+				op.Err = op.Query(dogs).Where("Breed", "").Exec(context.Background())
+				return op
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			mockDB.On("Query", mock.Anything, indexQuery(tc.index), mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+
+			op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+			result := tc.operation(op, &[]TestDog{})
+			require.NoError(t, result.Err)
+		})
+	}
+}
+
+func TestPlanWhereV4_MovesIndexedConditionIntoKey(t *testing.T) {
+	expr, index, err := planWhereV4(&[]TestDog{}, "test_dog", []WhereV4Condition{
+		{FieldName: "Name", FieldValues: []interface{}{"Buddy"}},
+		{FieldName: "Breed", FieldValues: []interface{}{"Dalmatian"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "breed-index", aws.ToString(index))
+	require.Contains(t, aws.ToString(expr.KeyCondition()), "AND")
+
+	names := map[string]bool{}
+	for _, name := range expr.Names() {
+		names[name] = true
+	}
+	require.True(t, names["Breed"])
+	require.True(t, names["Name"])
+}
+
+func TestPlanWhereV4_EmptyValueMatchesMissingAttribute(t *testing.T) {
+	// Empty index keys are not stored, so an equality with "" cannot use the index and has to
+	// match items without the attribute as well
+	expr, index, err := planWhereV4(&[]TestDog{}, "test_dog", []WhereV4Condition{
+		{FieldName: "Breed", FieldValues: []interface{}{""}},
+	})
+	require.NoError(t, err)
+	require.Nil(t, index)
+	require.Contains(t, aws.ToString(expr.Filter()), "attribute_not_exists")
+	require.NotContains(t, aws.ToString(expr.KeyCondition()), "AND")
+
+	var empty bool
+	for _, value := range expr.Values() {
+		if s, ok := value.(*types.AttributeValueMemberS); ok && s.Value == "" {
+			empty = true
+		}
+	}
+	require.True(t, empty)
+
+	expr, _, err = planWhereV4(&[]TestDog{}, "test_dog", []WhereV4Condition{
+		{FieldName: "Breed", FieldValues: []interface{}{"", "Lab"}},
+	})
+	require.NoError(t, err)
+	require.Contains(t, aws.ToString(expr.Filter()), "attribute_not_exists")
+}
+
+func TestMetaFor_InvalidIndexField(t *testing.T) {
+	type BadIndex struct {
+		Model
+		Tags []string `mm:"index=tags-index"`
+	}
+
+	_, err := metaFor(reflect.TypeOf(BadIndex{}))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "must be a string, number or []byte")
+}
+
+func TestTableIndexes_Conflicts(t *testing.T) {
+	type OtherDog struct {
+		Model
+		Color string `mm:"index=breed-index"`
+	}
+
+	_, err := tableIndexes([]interface{}{TestDog{}, OtherDog{}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "declared on both Breed and Color")
+
+	indexes, err := tableIndexes([]interface{}{TestDog{}, &[]TestDog{}})
+	require.NoError(t, err)
+	require.Len(t, indexes, 2)
+}
+
+func TestOperator_CreateDynamoDBTable_Indexes(t *testing.T) {
+	poll := indexPollInterval
+	indexPollInterval = 0
+	t.Cleanup(func() { indexPollInterval = poll })
+
+	activeTable := func(statuses ...types.IndexStatus) *dynamodb.DescribeTableOutput {
+		table := &types.TableDescription{TableStatus: types.TableStatusActive}
+		for i, status := range statuses {
+			name := []string{"breed-index", "age-index"}[i]
+			table.GlobalSecondaryIndexes = append(table.GlobalSecondaryIndexes, types.GlobalSecondaryIndexDescription{
+				IndexName:   aws.String(name),
+				IndexStatus: status,
+			})
+		}
+		return &dynamodb.DescribeTableOutput{Table: table}
+	}
+
+	t.Run("new_table", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("CreateTable", mock.Anything, mock.MatchedBy(func(in *dynamodb.CreateTableInput) bool {
+			return len(in.GlobalSecondaryIndexes) == 2 && len(in.AttributeDefinitions) == 4
+		}), mock.Anything).Return(&dynamodb.CreateTableOutput{}, nil)
+		mockDB.On("DescribeTable", mock.Anything, mock.Anything, mock.Anything).Return(activeTable(types.IndexStatusActive, types.IndexStatusActive), nil)
+
+		op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+		require.NoError(t, op.createDynamoDBTable(context.Background(), TableOptions{Models: []interface{}{TestDog{}}}))
+	})
+
+	t.Run("new_table_with_time_indexes", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("CreateTable", mock.Anything, mock.MatchedBy(func(in *dynamodb.CreateTableInput) bool {
+			return len(in.LocalSecondaryIndexes) == 2 && len(in.GlobalSecondaryIndexes) == 0 && len(in.AttributeDefinitions) == 4
+		}), mock.Anything).Return(&dynamodb.CreateTableOutput{}, nil)
+		mockDB.On("DescribeTable", mock.Anything, mock.Anything, mock.Anything).Return(activeTable(), nil)
+
+		op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+		require.NoError(t, op.createDynamoDBTable(context.Background(), TableOptions{TimeIndexes: true}))
+	})
+
+	t.Run("existing_table_without_time_indexes", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("CreateTable", mock.Anything, mock.Anything, mock.Anything).Return(nil, &types.ResourceInUseException{})
+		mockDB.On("DescribeTable", mock.Anything, mock.Anything, mock.Anything).Return(activeTable(), nil).Once()
+
+		op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+		err := op.createDynamoDBTable(context.Background(), TableOptions{TimeIndexes: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "can only be added when the table is created")
+	})
+
+	t.Run("existing_table_adds_missing_index", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("CreateTable", mock.Anything, mock.Anything, mock.Anything).Return(nil, &types.ResourceInUseException{})
+		mockDB.On("DescribeTable", mock.Anything, mock.Anything, mock.Anything).Return(activeTable(types.IndexStatusActive), nil).Once()
+		mockDB.On("UpdateTable", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateTableInput) bool {
+			return aws.ToString(in.GlobalSecondaryIndexUpdates[0].Create.IndexName) == "age-index"
+		}), mock.Anything).Return(&dynamodb.UpdateTableOutput{}, nil).Once()
+		mockDB.On("DescribeTable", mock.Anything, mock.Anything, mock.Anything).Return(activeTable(types.IndexStatusActive, types.IndexStatusCreating), nil).Once()
+		mockDB.On("DescribeTable", mock.Anything, mock.Anything, mock.Anything).Return(activeTable(types.IndexStatusActive, types.IndexStatusActive), nil).Once()
+
+		op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+		require.NoError(t, op.createDynamoDBTable(context.Background(), TableOptions{Models: []interface{}{TestDog{}}}))
+	})
+}
+
+func TestOperator_Create_OmitsEmptyIndexKeys(t *testing.T) {
+	mockDB := mocks.NewDynamoDBAPI(t)
+	mockDB.On("PutItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		_, hasBreed := in.Item["Breed"]
+		_, hasName := in.Item["Name"]
+		return !hasBreed && hasName
+	}), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil)
+
+	op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+	require.NoError(t, op.Create(&TestDog{Name: "Buddy"}).Err)
+}
+
+func TestOperator_Save_TimestampsSortChronologically(t *testing.T) {
+	// RFC3339Nano drops trailing zeros, so times written in the same second such as 12:00:00.5Z and
+	// 12:00:00.55Z would sort the wrong way round in the time indexes, as would times in other zones
+	base := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	times := []time.Time{
+		base.Add(500 * time.Millisecond),
+		base.Add(550 * time.Millisecond).In(time.FixedZone("CST", -6*60*60)),
+		base.Add(550*time.Millisecond + time.Nanosecond),
+		base.Add(time.Second),
+	}
+
+	var stored []map[string]types.AttributeValue
+	mockDB := mocks.NewDynamoDBAPI(t)
+	mockDB.On("PutItem", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = append(stored, args.Get(1).(*dynamodb.PutItemInput).Item)
+	}).Return(&dynamodb.PutItemOutput{}, nil).Times(len(times))
+
+	op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+	for i, createdAt := range times {
+		dog := TestDog{Model: Model{ID: fmt.Sprint(i), Type: "test_dog", CreatedAt: createdAt, UpdatedAt: createdAt}}
+		require.NoError(t, op.Save(&dog).Err)
+	}
+
+	require.Len(t, stored, len(times))
+	for i, item := range stored {
+		value := item["CreatedAt"].(*types.AttributeValueMemberS).Value
+		require.Len(t, value, len("2006-01-02T15:04:05.000000000Z"), value)
+		if i > 0 {
+			require.Less(t, stored[i-1]["CreatedAt"].(*types.AttributeValueMemberS).Value, value)
+		}
+
+		// Nothing is lost when the item is loaded again
+		var dog TestDog
+		require.NoError(t, attributevalue.UnmarshalMap(item, &dog))
+		require.True(t, times[i].Equal(dog.CreatedAt), value)
+	}
+
+	// Conditions on the timestamps compare against values stored the same way
+	expr, _, err := planWhereV4(&[]TestDog{}, "test_dog", []WhereV4Condition{
+		{FieldName: "CreatedAt", FieldValues: []interface{}{Gt(times[0])}},
+	})
+	require.NoError(t, err)
+	var compared string
+	for _, value := range expr.Values() {
+		if s, ok := value.(*types.AttributeValueMemberS); ok && strings.HasPrefix(s.Value, "2026") {
+			compared = s.Value
+		}
+	}
+	require.Equal(t, stored[0]["CreatedAt"].(*types.AttributeValueMemberS).Value, compared)
+}
@@ -0,0 +1,247 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tagName is the struct tag holding magicmodel options, e.g. `mm:"version"`
+const tagName = "mm"
+
+// modelMeta is the struct tag metadata of a model type, resolved once per type and cached
+type modelMeta struct {
+	// version is the index of the field tagged mm:"version", nil when the model is not versioned
+	version          []int
+	versionName      string
+	versionAttribute string
+	// indexes are the global secondary indexes declared with mm:"index=name" tags
+	indexes []indexMeta
+	// rules are the validation rules of each field that declares any, by field name
+	rules map[string]*fieldRules
+	// fields maps the stored fields to their attribute names
+	fields *fieldMap
+}
+
+var metaCache sync.Map
+
+// metaFor returns the cached metadata of the struct type t, resolving it on first use
+func metaFor(t reflect.Type) (*modelMeta, error) {
+	if cached, ok := metaCache.Load(t); ok {
+		return cached.(*modelMeta), nil
+	}
+
+	meta := &modelMeta{fields: fieldsOf(t)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		options := parseTag(field.Tag.Get(tagName))
+
+		if _, ok := options["version"]; ok {
+			if meta.version != nil {
+				return nil, validationErrorf("struct %s has more than one field tagged %s:\"version\"", t.Name(), tagName)
+			}
+			switch field.Type.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			default:
+				return nil, validationErrorf("version field %s.%s must be a signed integer, got %s", t.Name(), field.Name, field.Type)
+			}
+			meta.version = field.Index
+			meta.versionName = field.Name
+			meta.versionAttribute = attributeName(field)
+		}
+
+		if name, ok := options["index"]; ok {
+			index, err := newIndexMeta(t, field, name)
+			if err != nil {
+				return nil, err
+			}
+			meta.indexes = append(meta.indexes, index)
+		}
+
+		rules, err := newFieldRules(t, field, options)
+		if err != nil {
+			return nil, err
+		}
+		if rules != nil {
+			if meta.rules == nil {
+				meta.rules = map[string]*fieldRules{}
+			}
+			meta.rules[field.Name] = rules
+		}
+	}
+
+	cached, _ := metaCache.LoadOrStore(t, meta)
+	return cached.(*modelMeta), nil
+}
+
+// metaOf returns the metadata of the model q holds, which may be a struct or a slice of structs
+// behind any number of pointers
+func metaOf(q interface{}) (*modelMeta, error) {
+	t := reflect.TypeOf(q)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, validationErrorf("expected a struct or slice of structs, got %s", t.Kind())
+	}
+	return metaFor(t)
+}
+
+// storedField is a struct field that is marshalled into an item attribute
+type storedField struct {
+	name      string
+	attribute string
+	typ       reflect.Type
+}
+
+// fieldMap maps the Go names of the stored fields of a struct type to the attributes they are
+// marshalled to, which differ when a field is renamed with a dynamodbav:"name" tag. Fields of
+// embedded structs are stored at the top level and are included, unless shadowed by a field of
+// the outer struct. Queries, updates and projections name attributes through it
+type fieldMap struct {
+	byName      map[string]*storedField
+	byAttribute map[string]*storedField
+}
+
+var fieldMapCache sync.Map
+
+// fieldsOf returns the cached field map of the struct type t, resolving it on first use
+func fieldsOf(t reflect.Type) *fieldMap {
+	if cached, ok := fieldMapCache.Load(t); ok {
+		return cached.(*fieldMap)
+	}
+
+	fields := &fieldMap{byName: map[string]*storedField{}, byAttribute: map[string]*storedField{}}
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("dynamodbav"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && indirectType(field.Type).Kind() == reflect.Struct {
+			embedded = append(embedded, indirectType(field.Type))
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		fields.add(&storedField{name: field.Name, attribute: attributeName(field), typ: field.Type})
+	}
+	for _, e := range embedded {
+		for _, field := range fieldsOf(e).byName {
+			if _, ok := fields.byName[field.name]; !ok {
+				fields.add(field)
+			}
+		}
+	}
+
+	cached, _ := fieldMapCache.LoadOrStore(t, fields)
+	return cached.(*fieldMap)
+}
+
+func (m *fieldMap) add(field *storedField) {
+	m.byName[field.name] = field
+	if _, ok := m.byAttribute[field.attribute]; !ok {
+		m.byAttribute[field.attribute] = field
+	}
+}
+
+// attributeName returns the name of the attribute field is stored as
+func attributeName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("dynamodbav"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// attribute returns the attribute the field called name is stored as
+// name may already be an attribute name, and names of fields the map does not know are kept as is
+func (m *fieldMap) attribute(name string) string {
+	if field := m.lookup(name); field != nil {
+		return field.attribute
+	}
+	return name
+}
+
+// attributePath translates a dotted path of fields, e.g. "Home.Address.City" or "Pets[0].Name",
+// to the path of the attributes they are stored as, following nested structs
+// Each part may be a field or an attribute name, and parts that are not struct fields, such as
+// map keys, are kept as is. A nil map keeps the whole path
+func (m *fieldMap) attributePath(path string) string {
+	attributes, _ := m.resolve(path)
+	return attributes
+}
+
+// fieldPath translates a path like attributePath does, but to the Go names of the fields
+func (m *fieldMap) fieldPath(path string) string {
+	_, names := m.resolve(path)
+	return names
+}
+
+func (m *fieldMap) resolve(path string) (string, string) {
+	if m == nil {
+		return path, path
+	}
+
+	parts := strings.Split(path, ".")
+	attributes := make([]string, len(parts))
+	names := make([]string, len(parts))
+	current := m
+	for i, part := range parts {
+		name, indexes, _ := strings.Cut(part, "[")
+		if indexes != "" {
+			indexes = "[" + indexes
+		}
+
+		field := current.lookup(name)
+		if field == nil {
+			copy(attributes[i:], parts[i:])
+			copy(names[i:], parts[i:])
+			break
+		}
+		attributes[i] = field.attribute + indexes
+		names[i] = field.name + indexes
+
+		// Each index steps into an element of a list
+		t := indirectType(field.typ)
+		for n := strings.Count(indexes, "["); n > 0 && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array); n-- {
+			t = indirectType(t.Elem())
+		}
+		current = nil
+		if t.Kind() == reflect.Struct && t != reflect.TypeOf(time.Time{}) {
+			current = fieldsOf(t)
+		}
+		if current == nil && i+1 < len(parts) {
+			copy(attributes[i+1:], parts[i+1:])
+			copy(names[i+1:], parts[i+1:])
+			break
+		}
+	}
+	return strings.Join(attributes, "."), strings.Join(names, ".")
+}
+
+// lookup finds a field by its Go name, or else by the attribute it is stored as
+func (m *fieldMap) lookup(name string) *storedField {
+	if field, ok := m.byName[name]; ok {
+		return field
+	}
+	return m.byAttribute[name]
+}
+
+// parseTag splits a comma separated mm tag into its options, e.g. "version,index=name"
+// Options without a value map to an empty string
+func parseTag(tag string) map[string]string {
+	options := map[string]string{}
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(part, "=")
+		options[key] = value
+	}
+	return options
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type TestRenamedHome struct {
+	City string `dynamodbav:"city"`
+}
+
+// TestRenamedDog stores its fields under attribute names that differ from the Go names
+type TestRenamedDog struct {
+	Model
+	Name    string            `dynamodbav:"name"`
+	Breed   string            `dynamodbav:"breed" mm:"index=renamed-breed-index"`
+	Home    TestRenamedHome   `dynamodbav:"home"`
+	Pets    []TestRenamedHome `dynamodbav:"pets"`
+	Labels  map[string]string `dynamodbav:"labels,omitempty"`
+	Scratch string            `dynamodbav:"-"`
+	Version int64             `dynamodbav:"v" mm:"version"`
+}
+
+func TestFieldMap_Paths(t *testing.T) {
+	fields := fieldsOf(reflect.TypeOf(TestRenamedDog{}))
+
+	tests := []struct {
+		path       string
+		attributes string
+		names      string
+	}{
+		{path: "Name", attributes: "name", names: "Name"},
+		{path: "name", attributes: "name", names: "Name"},
+		{path: "ID", attributes: "ID", names: "ID"},
+		{path: "Home.City", attributes: "home.city", names: "Home.City"},
+		{path: "home.city", attributes: "home.city", names: "Home.City"},
+		{path: "Pets[1].City", attributes: "pets[1].city", names: "Pets[1].City"},
+		{path: "Labels.Color", attributes: "labels.Color", names: "Labels.Color"},
+		{path: "Scratch", attributes: "Scratch", names: "Scratch"},
+		{path: "Unknown.City", attributes: "Unknown.City", names: "Unknown.City"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			require.Equal(t, tc.attributes, fields.attributePath(tc.path))
+			require.Equal(t, tc.names, fields.fieldPath(tc.path))
+		})
+	}
+}
+
+func TestOperator_RenamedAttributes(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupMock func(*mocks.DynamoDBAPI)
+		operation func(*Operator) error
+	}{
+		{
+			name: "update_fields",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					names := updatedNames(in)
+					return len(names) == 3 && names["name"] && names["UpdatedAt"] && names["v"]
+				}), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+			},
+			operation: func(op *Operator) error {
+				dog := &TestRenamedDog{Model: Model{ID: "1", Type: "test_renamed_dog"}}
+				return op.UpdateFields(dog, map[string]interface{}{"Name": "Rex"}).Err
+			},
+		},
+		{
+			name: "where_on_index",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return aws.ToString(in.IndexName) == "renamed-breed-index" && hasName(in.ExpressionAttributeNames, "breed")
+				}), mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+			},
+			operation: func(op *Operator) error {
+				var dogs []TestRenamedDog
+				return op.Where(&dogs, "Breed", "Lab").Err
+			},
+		},
+		{
+			name: "query_nested_filter",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return hasName(in.ExpressionAttributeNames, "home") && hasName(in.ExpressionAttributeNames, "city") &&
+						!hasName(in.ExpressionAttributeNames, "Home")
+				}), mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+			},
+			operation: func(op *Operator) error {
+				var dogs []TestRenamedDog
+				return op.Query(&dogs).Filter(Or(Field("Home.City", "Austin"), Field("Name", "Rex"))).Exec(context.Background())
+			},
+		},
+		{
+			name: "select",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("GetItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.GetItemInput) bool {
+					return reflect.DeepEqual(projectedPaths(in.ProjectionExpression, in.ExpressionAttributeNames), []string{"name", "home.city"})
+				}), mock.Anything).Return(&dynamodb.GetItemOutput{Item: storedItem(t, TestRenamedDog{Name: "Rex"})}, nil).Once()
+			},
+			operation: func(op *Operator) error {
+				var dog TestRenamedDog
+				return op.Select("Name", "Home.City").Find(&dog, "1").Err
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			tc.setupMock(mockDB)
+			require.NoError(t, tc.operation(NewMagicModelOperatorWithClient(mockDB, "test-table")))
+		})
+	}
+}
+
+func TestWhereV3_FiltersRenamedAttributesInMemory(t *testing.T) {
+	dogs := []TestRenamedDog{{Name: "Rex", Home: TestRenamedHome{City: "Austin"}}, {Name: "Buddy", Home: TestRenamedHome{City: "Houston"}}}
+
+	op := NewMagicModelOperatorWithClient(mocks.NewDynamoDBAPI(t), "test-table")
+	require.NoError(t, op.WhereV3(false, &dogs, "home.city", "Austin").Err)
+	require.Len(t, dogs, 1)
+	require.Equal(t, "Rex", dogs[0].Name)
+}
+
+func hasName(names map[string]string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
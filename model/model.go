@@ -9,3 +9,11 @@ type Model struct {
 	UpdatedAt time.Time
 	DeletedAt *time.Time
 }
+
+// Modeler is satisfied by every struct that embeds Model, even through another struct, and by pointers
+// to them. It is the constraint of Repo, which NewRepo narrows to direct struct types
+type Modeler interface {
+	isModel()
+}
+
+func (Model) isModel() {}
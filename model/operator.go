@@ -13,12 +13,22 @@ import (
 )
 
 type Operator struct {
-	Err               error
-	IsWhereChain      bool
+	Err error
+	// Deprecated: WhereV2 and WhereV3 chains store their state on the shared Operator, which is not
+	// safe for concurrent use. Build queries with Operator.Query instead
+	IsWhereChain bool
+	// Deprecated: WhereV4 chains store their conditions on the shared Operator, which is not
+	// safe for concurrent use. Build queries with Operator.Query instead
 	PendingConditions []WhereV4Condition
-	IsWhereV4Chain    bool
-	db                DynamoDBAPI
-	tableName         string
+	// Deprecated: see PendingConditions
+	IsWhereV4Chain bool
+	db             DynamoDBAPI
+	tableName      string
+	ctx            context.Context
+	limits         QueryLimits
+	cursorSecret   []byte
+	projection     []string
+	snapshots      *snapshots
 }
 
 type WhereV4Condition struct {
@@ -32,14 +42,29 @@ type Deconstructed struct {
 	FieldType  string
 }
 
-// For backward compatibility
-var svc DynamoDBAPI
-var dynamoDBTableName string
+// TableOptions configures the table NewMagicModelOperatorWithOptions creates
+type TableOptions struct {
+	// Models lists the models stored in the table. Fields tagged mm:"index=name" are provisioned
+	// as global secondary indexes, and added to the table if it already exists without them
+	Models []interface{}
+	// TimeIndexes provisions local secondary indexes on CreatedAt and UpdatedAt so Query can
+	// order items by them. Local indexes can only be created along with the table
+	// They limit the items of each model Type, which share a partition key, to 10 GB in total
+	// including the index entries. Past that, DynamoDB rejects every write of the model with an
+	// ItemCollectionSizeLimitExceededException, so leave this off for models that may grow larger
+	TimeIndexes bool
+}
 
 func NewMagicModelOperator(ctx context.Context, tableName string, endpoint *string, optFns ...func(options *config.LoadOptions) error) (*Operator, error) {
+	return NewMagicModelOperatorWithOptions(ctx, tableName, endpoint, TableOptions{}, optFns...)
+}
+
+// NewMagicModelOperatorWithOptions creates a new operator like NewMagicModelOperator,
+// provisioning the table as described by opts
+func NewMagicModelOperatorWithOptions(ctx context.Context, tableName string, endpoint *string, opts TableOptions, optFns ...func(options *config.LoadOptions) error) (*Operator, error) {
 	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
 	if err != nil {
-		return nil, fmt.Errorf("an error occurred when getting aws config %s", err)
+		return nil, fmt.Errorf("an error occurred when getting aws config: %w", err)
 	}
 
 	var optFnsDynamodb []func(*dynamodb.Options)
@@ -51,10 +76,6 @@ func NewMagicModelOperator(ctx context.Context, tableName string, endpoint *stri
 
 	dbClient := dynamodb.NewFromConfig(cfg, optFnsDynamodb...)
 
-	// For backward compatibility
-	svc = dbClient
-	dynamoDBTableName = tableName
-
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 
 	operator := &Operator{
@@ -63,9 +84,9 @@ func NewMagicModelOperator(ctx context.Context, tableName string, endpoint *stri
 		tableName: tableName,
 	}
 
-	err = operator.createDynamoDBTable(ctx)
+	err = operator.createDynamoDBTable(ctx, opts)
 	if err != nil {
-		return nil, fmt.Errorf("encountered an error while creating DynamoDb table %s: %s", tableName, err)
+		return nil, fmt.Errorf("encountered an error while creating DynamoDb table %s: %w", tableName, err)
 	}
 
 	return operator, nil
@@ -74,10 +95,6 @@ func NewMagicModelOperator(ctx context.Context, tableName string, endpoint *stri
 // NewMagicModelOperatorWithClient creates a new operator with a custom DynamoDB client
 // This is useful for testing with mock clients
 func NewMagicModelOperatorWithClient(dbClient DynamoDBAPI, tableName string) *Operator {
-	// For backward compatibility
-	svc = dbClient
-	dynamoDBTableName = tableName
-
 	return &Operator{
 		Err:       nil,
 		db:        dbClient,
@@ -85,17 +102,48 @@ func NewMagicModelOperatorWithClient(dbClient DynamoDBAPI, tableName string) *Op
 	}
 }
 
-func (o *Operator) createDynamoDBTable(ctx context.Context) error {
+// WithContext returns a copy of the operator that passes ctx to every DynamoDB call
+// This allows callers to cancel in-flight requests or enforce deadlines
+func (o *Operator) WithContext(ctx context.Context) *Operator {
+	if ctx == nil {
+		panic("nil context")
+	}
+	op := *o
+	op.ctx = ctx
+	return &op
+}
+
+// Context returns the context used for DynamoDB calls
+// It defaults to context.Background() when no context has been set with WithContext
+func (o *Operator) Context() context.Context {
+	if o.ctx != nil {
+		return o.ctx
+	}
+	return context.Background()
+}
+
+func (o *Operator) createDynamoDBTable(ctx context.Context, opts TableOptions) error {
+	indexes, err := tableIndexes(opts.Models)
+	if err != nil {
+		return fmt.Errorf("encountered an error during init operation: %w", err)
+	}
+
+	var globalIndexes []types.GlobalSecondaryIndex
+	for _, index := range indexes {
+		globalIndexes = append(globalIndexes, index.definition())
+	}
+
+	var localIndexes []types.LocalSecondaryIndex
+	if opts.TimeIndexes {
+		localIndexes = timeIndexDefinitions()
+	}
+
 	// create DYNAMO DB table
-	_, err := o.db.CreateTable(ctx, &dynamodb.CreateTableInput{
-		TableName: aws.String(o.tableName),
-		AttributeDefinitions: []types.AttributeDefinition{{
-			AttributeName: aws.String("Type"),
-			AttributeType: types.ScalarAttributeTypeS,
-		}, {
-			AttributeName: aws.String("ID"),
-			AttributeType: types.ScalarAttributeTypeS,
-		}},
+	_, err = o.db.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName:              aws.String(o.tableName),
+		AttributeDefinitions:   attributeDefinitions(indexes, opts.TimeIndexes),
+		GlobalSecondaryIndexes: globalIndexes,
+		LocalSecondaryIndexes:  localIndexes,
 		KeySchema: []types.KeySchemaElement{{
 			AttributeName: aws.String("Type"),
 			KeyType:       types.KeyTypeHash,
@@ -108,8 +156,8 @@ func (o *Operator) createDynamoDBTable(ctx context.Context) error {
 	if err != nil {
 		var resourceInUse *types.ResourceInUseException
 		if errors.As(err, &resourceInUse) {
-			// Table already exists — that's fine, just continue
-			return nil
+			// Table already exists — that's fine, just make sure it has every index
+			return o.addMissingIndexes(ctx, indexes, opts.TimeIndexes)
 		}
 		// Unexpected error
 		return fmt.Errorf("encountered an error during init operation: %w", err)
@@ -121,7 +169,7 @@ func (o *Operator) createDynamoDBTable(ctx context.Context) error {
 	})
 	_, err = waiter.WaitForOutput(ctx, &dynamodb.DescribeTableInput{TableName: &o.tableName}, 30*time.Second)
 	if err != nil {
-		return fmt.Errorf("error while waiting for table to be created: %s", err)
+		return fmt.Errorf("error while waiting for table to be created: %w", err)
 	}
 
 	return nil
@@ -1,7 +1,13 @@
 package model
 
 import (
+	"context"
 	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
@@ -23,3 +29,61 @@ func TestNewMagicModelOperatorWithClient(t *testing.T) {
 		t.Errorf("Expected Err to be nil, got %v", op.Err)
 	}
 }
+
+type ctxKey struct{}
+
+func TestOperator_WithContext(t *testing.T) {
+	mockDB := mocks.NewDynamoDBAPI(t)
+	op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request-1")
+	isRequestCtx := mock.MatchedBy(func(c context.Context) bool {
+		return c.Value(ctxKey{}) == "request-1"
+	})
+	mockDB.On("PutItem", isRequestCtx, mock.Anything, mock.Anything).Return(&dynamodb.PutItemOutput{}, nil)
+
+	scoped := op.WithContext(ctx)
+	require.NotSame(t, op, scoped)
+	assert.Equal(t, context.Background(), op.Context())
+	assert.Equal(t, ctx, scoped.Context())
+
+	result := scoped.Create(&TestUser{Name: "John Doe"})
+	require.NoError(t, result.Err)
+	mockDB.AssertExpectations(t)
+}
+
+func TestOperator_WithContext_Canceled(t *testing.T) {
+	mockDB := mocks.NewDynamoDBAPI(t)
+	op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	mockDB.On("GetItem", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(c context.Context, _ *dynamodb.GetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+			return nil, c.Err()
+		})
+
+	result := op.WithContext(ctx).Find(&TestUser{}, "1")
+	require.Error(t, result.Err)
+	assert.Contains(t, result.Err.Error(), context.Canceled.Error())
+	assert.NoError(t, op.Err, "errors on a scoped operator must not leak into the parent")
+}
+
+func TestOperator_MultipleOperatorsCoexist(t *testing.T) {
+	usersDB := mocks.NewDynamoDBAPI(t)
+	petsDB := mocks.NewDynamoDBAPI(t)
+	users := NewMagicModelOperatorWithClient(usersDB, "users-table")
+	pets := NewMagicModelOperatorWithClient(petsDB, "pets-table")
+
+	forTable := func(name string) interface{} {
+		return mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+			return aws.ToString(in.TableName) == name
+		})
+	}
+	usersDB.On("PutItem", mock.Anything, forTable("users-table"), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	petsDB.On("PutItem", mock.Anything, forTable("pets-table"), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	// Creating the second operator must not redirect the first one's writes
+	require.NoError(t, users.Create(&TestUser{Name: "John Doe"}).Err)
+	require.NoError(t, pets.Create(&TestUser{Name: "Rex"}).Err)
+}
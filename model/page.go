@@ -0,0 +1,240 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"strings"
+)
+
+// DefaultPageLimit is the page size used when PageOptions.Limit is not set
+const DefaultPageLimit = 25
+
+// ErrInvalidCursor is returned when a page cursor is malformed, has been tampered with,
+// or was issued for a different table, model Type or filter
+var ErrInvalidCursor = errors.New("invalid page cursor")
+
+// defaultCursorSecret signs cursors for operators without a secret of their own
+// It is generated per process, so cursors only survive restarts when WithCursorSecret is used
+var defaultCursorSecret = func() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(fmt.Sprintf("unable to generate page cursor secret: %v", err))
+	}
+	return secret
+}()
+
+// PageOptions configures a single page of results
+type PageOptions struct {
+	// Limit is the maximum number of items in the page, DefaultPageLimit when zero
+	Limit int32
+	// Cursor is the token returned by the previous call to Page, empty for the first page
+	Cursor string
+	// Conditions filters the page the same way as WhereV4, an empty list returns the same items as All
+	Conditions []WhereV4Condition
+}
+
+// pageCursor is the signed payload behind the opaque cursor returned by Page
+type pageCursor struct {
+	Scope string                    `json:"s"`
+	Key   map[string]cursorKeyValue `json:"k"`
+}
+
+// cursorKeyValue holds a key attribute, which DynamoDB restricts to string, number or binary
+type cursorKeyValue struct {
+	S *string `json:"S,omitempty"`
+	N *string `json:"N,omitempty"`
+	B []byte  `json:"B,omitempty"`
+}
+
+// WithCursorSecret returns a copy of the operator that signs page cursors with secret
+// Use the same secret on every instance of a service so cursors can be redeemed by any of them
+func (o *Operator) WithCursorSecret(secret []byte) *Operator {
+	op := *o
+	op.cursorSecret = secret
+	return &op
+}
+
+// Page loads a single page of items into q and returns the cursor for the next page
+// An empty cursor means there are no more items. Cursors are URL-safe and signed, and are
+// rejected with ErrInvalidCursor when replayed against a different model Type or filter
+func (o *Operator) Page(q interface{}, opts PageOptions) (string, error) {
+	if o.Err != nil {
+		return "", o.Err
+	}
+
+	name, err := ParseModelName(q)
+	if err != nil {
+		return "", err
+	}
+
+	err = validateInputSlice(q, "Page", name)
+	if err != nil {
+		return "", err
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+
+	expr, index, err := planWhereV4(q, name, opts.Conditions, o.projection...)
+	if err != nil {
+		return "", newOperationError("Page", q, err)
+	}
+
+	scope, err := o.cursorScope(name, expr)
+	if err != nil {
+		return "", newOperationError("Page", q, err)
+	}
+
+	var startKey map[string]types.AttributeValue
+	if opts.Cursor != "" {
+		startKey, err = o.decodeCursor(opts.Cursor, scope)
+		if err != nil {
+			return "", newOperationError("Page", q, err)
+		}
+	}
+
+	params := &dynamodb.QueryInput{
+		TableName:                 aws.String(o.tableName),
+		IndexName:                 index,
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		ExclusiveStartKey:         startKey,
+	}
+
+	items, err := o.queryUpTo(params, limit)
+	if err != nil {
+		return "", newOperationError("Page", q, err)
+	}
+
+	err = attributevalue.UnmarshalListOfMaps(items, q)
+	if err != nil {
+		return "", newOperationError("Page", q, err)
+	}
+	o.track(q)
+
+	if len(params.ExclusiveStartKey) == 0 {
+		return "", nil
+	}
+
+	cursor, err := o.encodeCursor(params.ExclusiveStartKey, scope)
+	if err != nil {
+		return "", newOperationError("Page", q, err)
+	}
+	return cursor, nil
+}
+
+// cursorScope fingerprints the table, model Type and query expression a cursor belongs to
+func (o *Operator) cursorScope(typeName string, expr expression.Expression) (string, error) {
+	var values map[string]interface{}
+	if err := attributevalue.UnmarshalMap(expr.Values(), &values); err != nil {
+		return "", err
+	}
+
+	fingerprint, err := json.Marshal([]interface{}{
+		o.tableName,
+		typeName,
+		aws.ToString(expr.KeyCondition()),
+		aws.ToString(expr.Filter()),
+		expr.Names(),
+		values,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(fingerprint)
+	return base64.RawURLEncoding.EncodeToString(sum[:16]), nil
+}
+
+// encodeCursor serializes and signs a LastEvaluatedKey
+func (o *Operator) encodeCursor(key map[string]types.AttributeValue, scope string) (string, error) {
+	cursor := pageCursor{Scope: scope, Key: make(map[string]cursorKeyValue, len(key))}
+	for name, av := range key {
+		switch v := av.(type) {
+		case *types.AttributeValueMemberS:
+			cursor.Key[name] = cursorKeyValue{S: aws.String(v.Value)}
+		case *types.AttributeValueMemberN:
+			cursor.Key[name] = cursorKeyValue{N: aws.String(v.Value)}
+		case *types.AttributeValueMemberB:
+			cursor.Key[name] = cursorKeyValue{B: v.Value}
+		default:
+			return "", fmt.Errorf("unsupported key attribute type %T for %s", av, name)
+		}
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(o.signCursor(payload)), nil
+}
+
+// decodeCursor verifies a cursor's signature and scope and returns the key it encodes
+func (o *Operator) decodeCursor(token, scope string) (map[string]types.AttributeValue, error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidCursor)
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, o.signCursor(payload)) {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidCursor)
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidCursor)
+	}
+
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidCursor)
+	}
+
+	if cursor.Scope != scope {
+		return nil, fmt.Errorf("%w: cursor was issued for a different model type or filter", ErrInvalidCursor)
+	}
+
+	key := make(map[string]types.AttributeValue, len(cursor.Key))
+	for name, v := range cursor.Key {
+		switch {
+		case v.S != nil:
+			key[name] = &types.AttributeValueMemberS{Value: *v.S}
+		case v.N != nil:
+			key[name] = &types.AttributeValueMemberN{Value: *v.N}
+		case v.B != nil:
+			key[name] = &types.AttributeValueMemberB{Value: v.B}
+		default:
+			return nil, fmt.Errorf("%w: malformed key attribute %s", ErrInvalidCursor, name)
+		}
+	}
+	return key, nil
+}
+
+// signCursor computes the HMAC of a cursor payload
+func (o *Operator) signCursor(payload string) []byte {
+	secret := o.cursorSecret
+	if len(secret) == 0 {
+		secret = defaultCursorSecret
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
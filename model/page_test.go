@@ -0,0 +1,136 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPet is a second model type used to check that cursors are bound to a Type
+type TestPet struct {
+	Model
+	Name string
+}
+
+func testUserItem(id string) map[string]types.AttributeValue {
+	item, _ := attributevalue.MarshalMap(&TestUser{Model: Model{ID: id, Type: "test_user"}})
+	return item
+}
+
+func testUserKey(id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"Type": &types.AttributeValueMemberS{Value: "test_user"},
+		"ID":   &types.AttributeValueMemberS{Value: id},
+	}
+}
+
+func TestOperator_Page(t *testing.T) {
+	mockDB := mocks.NewDynamoDBAPI(t)
+	op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+
+	// First page: the filter drops an item, so Page keeps reading until the page is full
+	mockDB.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return in.ExclusiveStartKey == nil && aws.ToInt32(in.Limit) == 2
+	}), mock.Anything).Return(&dynamodb.QueryOutput{
+		Items:            []map[string]types.AttributeValue{testUserItem("1")},
+		LastEvaluatedKey: testUserKey("2"),
+	}, nil).Once()
+	mockDB.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		start, ok := in.ExclusiveStartKey["ID"].(*types.AttributeValueMemberS)
+		return ok && start.Value == "2" && aws.ToInt32(in.Limit) == 1
+	}), mock.Anything).Return(&dynamodb.QueryOutput{
+		Items:            []map[string]types.AttributeValue{testUserItem("3")},
+		LastEvaluatedKey: testUserKey("3"),
+	}, nil).Once()
+
+	var page []TestUser
+	cursor, err := op.Page(&page, PageOptions{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	require.NotEmpty(t, cursor)
+	require.NotContains(t, cursor, "+")
+	require.NotContains(t, cursor, "/")
+
+	// Second page: resumes from the cursor and reports the end of the result set
+	mockDB.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		start, ok := in.ExclusiveStartKey["ID"].(*types.AttributeValueMemberS)
+		return ok && start.Value == "3"
+	}), mock.Anything).Return(&dynamodb.QueryOutput{
+		Items: []map[string]types.AttributeValue{testUserItem("4")},
+	}, nil).Once()
+
+	page = nil
+	cursor, err = op.Page(&page, PageOptions{Limit: 2, Cursor: cursor})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Empty(t, cursor)
+}
+
+func TestOperator_Page_InvalidCursor(t *testing.T) {
+	op := NewMagicModelOperatorWithClient(mocks.NewDynamoDBAPI(t), "test-table")
+	conditions := []WhereV4Condition{{FieldName: "IsAdmin", FieldValues: []interface{}{true}}}
+
+	expr, err := buildWhereV4Expression("test_user", conditions)
+	require.NoError(t, err)
+	scope, err := op.cursorScope("test_user", expr)
+	require.NoError(t, err)
+	cursor, err := op.encodeCursor(testUserKey("1"), scope)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		page func() error
+	}{
+		{
+			name: "malformed",
+			page: func() error {
+				_, err := op.Page(&[]TestUser{}, PageOptions{Cursor: "not-a-cursor", Conditions: conditions})
+				return err
+			},
+		},
+		{
+			name: "tampered",
+			page: func() error {
+				_, err := op.Page(&[]TestUser{}, PageOptions{Cursor: "x" + cursor, Conditions: conditions})
+				return err
+			},
+		},
+		{
+			name: "different_secret",
+			page: func() error {
+				_, err := op.WithCursorSecret([]byte("other")).Page(&[]TestUser{}, PageOptions{Cursor: cursor, Conditions: conditions})
+				return err
+			},
+		},
+		{
+			name: "different_filter",
+			page: func() error {
+				other := []WhereV4Condition{{FieldName: "IsAdmin", FieldValues: []interface{}{false}}}
+				_, err := op.Page(&[]TestUser{}, PageOptions{Cursor: cursor, Conditions: other})
+				return err
+			},
+		},
+		{
+			name: "different_type",
+			page: func() error {
+				_, err := op.Page(&[]TestPet{}, PageOptions{Cursor: cursor, Conditions: conditions})
+				return err
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.page()
+			require.Error(t, err)
+			require.True(t, errors.Is(err, ErrInvalidCursor), err.Error())
+		})
+	}
+}
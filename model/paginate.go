@@ -0,0 +1,99 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ErrQueryLimitReached is returned when a query stops following LastEvaluatedKey
+// because it hit one of the operator's QueryLimits before the result set was complete
+var ErrQueryLimitReached = errors.New("query limit reached before the result set was complete")
+
+// QueryLimits caps how much data All and the Where queries read while paginating
+// A zero value means no limit
+type QueryLimits struct {
+	MaxItems int
+	MaxPages int
+}
+
+// WithQueryLimits returns a copy of the operator whose queries stop paginating once
+// either limit is reached. Items read up to that point are still returned alongside
+// an error wrapping ErrQueryLimitReached
+func (o *Operator) WithQueryLimits(limits QueryLimits) *Operator {
+	op := *o
+	op.limits = limits
+	return &op
+}
+
+// queryAllPages runs the query and follows LastEvaluatedKey until the result set is complete
+// If a query limit is reached, the items read so far are returned with ErrQueryLimitReached
+func (o *Operator) queryAllPages(input *dynamodb.QueryInput) ([]map[string]types.AttributeValue, error) {
+	params := *input
+	var items []map[string]types.AttributeValue
+
+	for pages := 1; ; pages++ {
+		response, err := o.db.Query(o.Context(), &params)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, response.Items...)
+
+		if o.limits.MaxItems > 0 && len(items) >= o.limits.MaxItems {
+			if len(items) > o.limits.MaxItems || len(response.LastEvaluatedKey) > 0 {
+				return items[:o.limits.MaxItems], fmt.Errorf("%w: stopped after %d items", ErrQueryLimitReached, o.limits.MaxItems)
+			}
+			return items, nil
+		}
+
+		if len(response.LastEvaluatedKey) == 0 {
+			return items, nil
+		}
+
+		if o.limits.MaxPages > 0 && pages >= o.limits.MaxPages {
+			return items, fmt.Errorf("%w: stopped after %d pages", ErrQueryLimitReached, pages)
+		}
+		params.ExclusiveStartKey = response.LastEvaluatedKey
+	}
+}
+
+// queryInto runs a paginated query and unmarshals every item into result
+// Partial results are still unmarshalled when a query limit is reached
+func (o *Operator) queryInto(input *dynamodb.QueryInput, result interface{}) error {
+	items, queryErr := o.queryAllPages(input)
+	if queryErr != nil && !errors.Is(queryErr, ErrQueryLimitReached) {
+		return queryErr
+	}
+
+	if err := attributevalue.UnmarshalListOfMaps(items, result); err != nil {
+		return err
+	}
+	o.track(result)
+	return queryErr
+}
+
+// queryUpTo reads pages until limit items passed the filter or the result set is exhausted
+// Limit caps the items DynamoDB evaluates, not the items that pass the filter, so each page asks
+// for the remaining count. params.ExclusiveStartKey is left at the key to resume from, if any
+func (o *Operator) queryUpTo(params *dynamodb.QueryInput, limit int32) ([]map[string]types.AttributeValue, error) {
+	var items []map[string]types.AttributeValue
+	for pages := 1; ; pages++ {
+		params.Limit = aws.Int32(limit - int32(len(items)))
+		response, err := o.db.Query(o.Context(), params)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, response.Items...)
+		params.ExclusiveStartKey = response.LastEvaluatedKey
+
+		if len(params.ExclusiveStartKey) == 0 || int32(len(items)) >= limit {
+			return items, nil
+		}
+		if o.limits.MaxPages > 0 && pages >= o.limits.MaxPages {
+			return items, nil
+		}
+	}
+}
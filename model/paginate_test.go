@@ -0,0 +1,146 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// queryPages sets up the mock to return the first n pages described by pageSizes,
+// with every page but the last carrying a LastEvaluatedKey
+func queryPages(dbMock *mocks.DynamoDBAPI, n int, pageSizes ...int) {
+	now := time.Now()
+	id := 0
+	for i, size := range pageSizes[:n] {
+		var items []map[string]types.AttributeValue
+		for j := 0; j < size; j++ {
+			id++
+			item, _ := attributevalue.MarshalMap(&TestUser{
+				Model: Model{ID: fmt.Sprint(id), Type: "test_user", CreatedAt: now, UpdatedAt: now},
+				Name:  fmt.Sprintf("user-%d", id),
+			})
+			items = append(items, item)
+		}
+
+		out := &dynamodb.QueryOutput{Items: items}
+		if i < len(pageSizes)-1 {
+			out.LastEvaluatedKey = map[string]types.AttributeValue{
+				"Type": &types.AttributeValueMemberS{Value: "test_user"},
+				"ID":   &types.AttributeValueMemberS{Value: fmt.Sprint(id)},
+			}
+		}
+
+		isPage := mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+			if i == 0 {
+				return in.ExclusiveStartKey == nil
+			}
+			start, ok := in.ExclusiveStartKey["ID"].(*types.AttributeValueMemberS)
+			return ok && start.Value == fmt.Sprint(sum(pageSizes[:i]))
+		})
+		dbMock.On("Query", mock.Anything, isPage, mock.Anything).Return(out, nil).Once()
+	}
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func TestOperator_Pagination(t *testing.T) {
+	tests := []struct {
+		name          string
+		pageSizes     []int
+		limits        QueryLimits
+		operations    func(*Operator, *[]TestUser) *Operator
+		expectLimit   bool
+		expectedItems int
+	}{
+		{
+			name:      "all_follows_every_page",
+			pageSizes: []int{2, 0, 3},
+			operations: func(op *Operator, result *[]TestUser) *Operator {
+				return op.All(result)
+			},
+			expectedItems: 5,
+		},
+		{
+			name:      "where_follows_every_page",
+			pageSizes: []int{1, 1},
+			operations: func(op *Operator, result *[]TestUser) *Operator {
+				return op.Where(result, "IsAdmin", false)
+			},
+			expectedItems: 2,
+		},
+		{
+			name:      "where_v4_follows_every_page",
+			pageSizes: []int{2, 2},
+			operations: func(op *Operator, result *[]TestUser) *Operator {
+				return op.WhereV4(false, result, "IsAdmin", false)
+			},
+			expectedItems: 4,
+		},
+		{
+			name:      "max_items_truncates",
+			pageSizes: []int{2, 2},
+			limits:    QueryLimits{MaxItems: 3},
+			operations: func(op *Operator, result *[]TestUser) *Operator {
+				return op.All(result)
+			},
+			expectLimit:   true,
+			expectedItems: 3,
+		},
+		{
+			name:      "max_items_exactly_complete",
+			pageSizes: []int{2, 2},
+			limits:    QueryLimits{MaxItems: 4},
+			operations: func(op *Operator, result *[]TestUser) *Operator {
+				return op.All(result)
+			},
+			expectedItems: 4,
+		},
+		{
+			name:      "max_pages_stops",
+			pageSizes: []int{2, 2},
+			limits:    QueryLimits{MaxPages: 1},
+			operations: func(op *Operator, result *[]TestUser) *Operator {
+				return op.All(result)
+			},
+			expectLimit:   true,
+			expectedItems: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			queried := len(tc.pageSizes)
+			if tc.limits.MaxPages > 0 {
+				queried = tc.limits.MaxPages
+			}
+			queryPages(mockDB, queried, tc.pageSizes...)
+
+			op := NewMagicModelOperatorWithClient(mockDB, "test-table").WithQueryLimits(tc.limits)
+			result := []TestUser{}
+			finalOp := tc.operations(op, &result)
+
+			if tc.expectLimit {
+				require.Error(t, finalOp.Err)
+				require.True(t, errors.Is(finalOp.Err, ErrQueryLimitReached))
+			} else {
+				require.NoError(t, finalOp.Err)
+			}
+			require.Len(t, result, tc.expectedItems)
+		})
+	}
+}
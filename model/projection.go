@@ -0,0 +1,29 @@
+package model
+
+import (
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+)
+
+// Select returns a copy of the operator whose Find, All, Where and Page calls only load the given
+// attributes. Nested attributes are separated by dots, e.g. "Home.Address.City", and fields that
+// are not selected are left zero-valued. Select with no fields loads whole items again
+// Saving a partially loaded item overwrites the attributes that were not selected, unless the operator
+// tracks changes with WithDirtyTracking
+func (o *Operator) Select(fields ...string) *Operator {
+	op := *o
+	op.projection = fields
+	return &op
+}
+
+// withProjection adds a projection of paths to b, leaving it unchanged when no paths are given
+// fields maps the field names in paths to the attributes they are stored as
+func withProjection(b expression.Builder, fields *fieldMap, paths []string) expression.Builder {
+	if len(paths) == 0 {
+		return b
+	}
+	names := make([]expression.NameBuilder, len(paths))
+	for i, path := range paths {
+		names[i] = expression.Name(fields.attributePath(path))
+	}
+	return b.WithProjection(expression.NamesList(names[0], names[1:]...))
+}
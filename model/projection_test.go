@@ -0,0 +1,137 @@
+package model
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// projectedPaths resolves the placeholders of a projection expression back to field paths
+func projectedPaths(expr *string, names map[string]string) []string {
+	var paths []string
+	for _, path := range strings.Split(aws.ToString(expr), ", ") {
+		parts := strings.Split(path, ".")
+		for i, placeholder := range parts {
+			parts[i] = names[placeholder]
+		}
+		paths = append(paths, strings.Join(parts, "."))
+	}
+	return paths
+}
+
+func TestOperator_Select(t *testing.T) {
+	fields := []string{"ID", "Name", "Home.Address.City"}
+
+	tests := []struct {
+		name      string
+		setupMock func(*mocks.DynamoDBAPI)
+		operation func(*Operator) error
+	}{
+		{
+			name: "find",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("GetItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.GetItemInput) bool {
+					return reflect.DeepEqual(fields, projectedPaths(in.ProjectionExpression, in.ExpressionAttributeNames))
+				}), mock.Anything).Return(&dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+					"ID":   &types.AttributeValueMemberS{Value: "1"},
+					"Name": &types.AttributeValueMemberS{Value: "John"},
+				}}, nil)
+			},
+			operation: func(op *Operator) error {
+				var user TestUser
+				err := op.Select(fields...).Find(&user, "1").Err
+				if err == nil {
+					require.Equal(t, "John", user.Name)
+					require.Empty(t, user.Email)
+					require.Zero(t, user.Age)
+				}
+				return err
+			},
+		},
+		{
+			name: "all",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return reflect.DeepEqual(fields, projectedPaths(in.ProjectionExpression, in.ExpressionAttributeNames))
+				}), mock.Anything).Return(&dynamodb.QueryOutput{}, nil)
+			},
+			operation: func(op *Operator) error {
+				return op.Select(fields...).All(&[]TestUser{}).Err
+			},
+		},
+		{
+			name: "where",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return reflect.DeepEqual(fields, projectedPaths(in.ProjectionExpression, in.ExpressionAttributeNames))
+				}), mock.Anything).Return(&dynamodb.QueryOutput{}, nil)
+			},
+			operation: func(op *Operator) error {
+				return op.Select(fields...).Where(&[]TestUser{}, "Email", "john@example.com").Err
+			},
+		},
+		{
+			name: "where_v3",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return reflect.DeepEqual(fields, projectedPaths(in.ProjectionExpression, in.ExpressionAttributeNames))
+				}), mock.Anything).Return(&dynamodb.QueryOutput{}, nil)
+			},
+			operation: func(op *Operator) error {
+				return op.Select(fields...).WhereV3(false, &[]TestUser{}, "Email", "john@example.com").Err
+			},
+		},
+		{
+			name: "where_v4",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return reflect.DeepEqual(fields, projectedPaths(in.ProjectionExpression, in.ExpressionAttributeNames))
+				}), mock.Anything).Return(&dynamodb.QueryOutput{}, nil)
+			},
+			operation: func(op *Operator) error {
+				return op.Select(fields...).WhereV4(false, &[]TestUser{}, "Age", Gt(3)).Err
+			},
+		},
+		{
+			name: "query",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return reflect.DeepEqual(fields, projectedPaths(in.ProjectionExpression, in.ExpressionAttributeNames))
+				}), mock.Anything).Return(&dynamodb.QueryOutput{}, nil)
+			},
+			operation: func(op *Operator) error {
+				return op.Query(&[]TestUser{}).Where("Age", 3).Select(fields...).Exec(context.Background())
+			},
+		},
+		{
+			name: "unselected_operator_loads_everything",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return in.ProjectionExpression == nil
+				}), mock.Anything).Return(&dynamodb.QueryOutput{}, nil)
+			},
+			operation: func(op *Operator) error {
+				op.Select(fields...)
+				return op.All(&[]TestUser{}).Err
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			tc.setupMock(mockDB)
+
+			op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+			require.NoError(t, tc.operation(op))
+		})
+	}
+}
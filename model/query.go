@@ -0,0 +1,176 @@
+package model
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// SortOrder is the direction a Query returns items in
+type SortOrder int
+
+const (
+	// Asc returns the oldest or smallest items first
+	Asc SortOrder = iota
+	// Desc returns the newest or largest items first
+	Desc
+)
+
+// Query builds a query over the items of a model
+// Each method returns a new Query and the Operator is never modified, so a partially built query
+// can be reused safely and one Operator can be shared by any number of goroutines
+type Query struct {
+	op         *Operator
+	q          interface{}
+	orderBy    string
+	order      SortOrder
+	limit      int32
+	conditions []WhereV4Condition
+	projection []string
+}
+
+// Query starts a query that loads items into the slice q points to when executed
+func (o *Operator) Query(q interface{}) *Query {
+	return &Query{op: o, q: q, projection: o.projection}
+}
+
+// OrderBy sorts the results by field, which must be CreatedAt or UpdatedAt when the table was
+// created with TimeIndexes, ID, or a field tagged with mm:"index=name"
+func (qb *Query) OrderBy(field string, order SortOrder) *Query {
+	next := *qb
+	next.orderBy = field
+	next.order = order
+	return &next
+}
+
+// Where only returns items whose field matches value, following the rules of WhereV4:
+// a slice matches any of its elements and a Comparison such as model.Gt(3) is applied to the field
+// Every Where and Filter condition must match
+func (qb *Query) Where(field string, value interface{}) *Query {
+	return qb.withCondition(WhereV4Condition{FieldName: field, FieldValues: normalizeFieldValues(value)})
+}
+
+// Filter only returns items matching c, e.g. model.Or(model.Field("Breed", "Lab"), model.Field("Age", model.Gt(3)))
+// Every Where and Filter condition must match
+func (qb *Query) Filter(c Condition) *Query {
+	return qb.withCondition(WhereV4Condition{FieldValues: []interface{}{c}})
+}
+
+// withCondition returns a copy of the query with condition added, leaving qb's conditions untouched
+func (qb *Query) withCondition(condition WhereV4Condition) *Query {
+	next := *qb
+	next.conditions = append(append([]WhereV4Condition{}, qb.conditions...), condition)
+	return &next
+}
+
+// Select only loads the given attributes, leaving the other fields zero-valued as in Operator.Select
+func (qb *Query) Select(fields ...string) *Query {
+	next := *qb
+	next.projection = fields
+	return &next
+}
+
+// Limit caps the number of items the query returns. Zero means no limit
+func (qb *Query) Limit(n int32) *Query {
+	next := *qb
+	next.limit = n
+	return &next
+}
+
+// Exec runs the query with ctx and loads the results into the slice passed to Operator.Query
+func (qb *Query) Exec(ctx context.Context) error {
+	o := qb.op.WithContext(ctx)
+	if o.Err != nil {
+		return o.Err
+	}
+
+	name, err := ParseModelName(qb.q)
+	if err != nil {
+		return err
+	}
+
+	err = validateInputSlice(qb.q, "Query", name)
+	if err != nil {
+		return err
+	}
+
+	expr, index, err := qb.plan(name)
+	if err != nil {
+		return newOperationError("Query", qb.q, err)
+	}
+
+	params := &dynamodb.QueryInput{
+		TableName:                 aws.String(o.tableName),
+		IndexName:                 index,
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		ScanIndexForward:          aws.Bool(qb.order == Asc),
+	}
+
+	if qb.limit <= 0 {
+		err = o.queryInto(params, qb.q)
+		if err != nil {
+			return newOperationError("Query", qb.q, err)
+		}
+		return nil
+	}
+
+	items, err := o.queryUpTo(params, qb.limit)
+	if err != nil {
+		return newOperationError("Query", qb.q, err)
+	}
+
+	err = attributevalue.UnmarshalListOfMaps(items, qb.q)
+	if err != nil {
+		return newOperationError("Query", qb.q, err)
+	}
+	o.track(qb.q)
+	return nil
+}
+
+// plan builds the query expression and picks the index to read. Without OrderBy a condition on an
+// indexed field can use that index as in WhereV4, otherwise the index that sorts by OrderBy is read
+func (qb *Query) plan(typeName string) (expression.Expression, *string, error) {
+	if qb.orderBy == "" {
+		return planWhereV4(qb.q, typeName, qb.conditions, qb.projection...)
+	}
+
+	index, err := qb.orderIndex()
+	if err != nil {
+		return expression.Expression{}, nil, err
+	}
+	meta, err := metaOf(qb.q)
+	if err != nil {
+		return expression.Expression{}, nil, err
+	}
+	expr, err := buildWhereV4KeyExpression(meta.fields, expression.Key("Type").Equal(expression.Value(typeName)), qb.conditions, qb.projection...)
+	return expr, index, err
+}
+
+// orderIndex returns the index sorted by the OrderBy field, or nil for the base table which is sorted by ID
+func (qb *Query) orderIndex() (*string, error) {
+	switch qb.orderBy {
+	case "", "ID":
+		return nil, nil
+	case "CreatedAt":
+		return aws.String(createdAtIndex), nil
+	case "UpdatedAt":
+		return aws.String(updatedAtIndex), nil
+	}
+
+	meta, err := metaOf(qb.q)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range meta.indexes {
+		if index.field == qb.orderBy || index.attribute == qb.orderBy {
+			return aws.String(index.name), nil
+		}
+	}
+	return nil, validationErrorf("cannot order by %s: it is not CreatedAt, UpdatedAt, ID or an indexed field", qb.orderBy)
+}
@@ -0,0 +1,184 @@
+package model
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery_Exec(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMock     func(*mocks.DynamoDBAPI)
+		build         func(*Query) *Query
+		expectCount   int
+		errorContains string
+	}{
+		{
+			name: "newest_first",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return aws.ToString(in.IndexName) == "created-at-index" &&
+						!aws.ToBool(in.ScanIndexForward) &&
+						aws.ToInt32(in.Limit) == 2
+				}), mock.Anything).Return(&dynamodb.QueryOutput{
+					Items:            []map[string]types.AttributeValue{testUserItem("2"), testUserItem("1")},
+					LastEvaluatedKey: testUserKey("1"),
+				}, nil).Once()
+			},
+			build: func(q *Query) *Query {
+				return q.OrderBy("CreatedAt", Desc).Limit(2)
+			},
+			expectCount: 2,
+		},
+		{
+			name: "keeps_reading_until_limit",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return aws.ToInt32(in.Limit) == 3
+				}), mock.Anything).Return(&dynamodb.QueryOutput{
+					Items:            []map[string]types.AttributeValue{testUserItem("1")},
+					LastEvaluatedKey: testUserKey("2"),
+				}, nil).Once()
+				dbMock.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return aws.ToInt32(in.Limit) == 2 && in.ExclusiveStartKey != nil
+				}), mock.Anything).Return(&dynamodb.QueryOutput{
+					Items: []map[string]types.AttributeValue{testUserItem("3"), testUserItem("4")},
+				}, nil).Once()
+			},
+			build: func(q *Query) *Query {
+				return q.OrderBy("UpdatedAt", Asc).Limit(3)
+			},
+			expectCount: 3,
+		},
+		{
+			name: "no_limit_reads_every_page",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+					return in.IndexName == nil && aws.ToBool(in.ScanIndexForward) && in.Limit == nil
+				}), mock.Anything).Return(&dynamodb.QueryOutput{
+					Items: []map[string]types.AttributeValue{testUserItem("1"), testUserItem("2")},
+				}, nil).Once()
+			},
+			build: func(q *Query) *Query {
+				return q
+			},
+			expectCount: 2,
+		},
+		{
+			name: "unsupported_order_field",
+			build: func(q *Query) *Query {
+				return q.OrderBy("Name", Asc)
+			},
+			errorContains: "cannot order by Name",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			if tc.setupMock != nil {
+				tc.setupMock(mockDB)
+			}
+
+			op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+			var users []TestUser
+			err := tc.build(op.Query(&users)).Exec(context.Background())
+
+			if tc.errorContains != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.errorContains)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, users, tc.expectCount)
+		})
+	}
+}
+
+func TestQuery_OrderByIndexedField(t *testing.T) {
+	mockDB := mocks.NewDynamoDBAPI(t)
+	mockDB.On("Query", mock.Anything, indexQuery("age-index"), mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+
+	op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+	var dogs []TestDog
+	require.NoError(t, op.Query(&dogs).OrderBy("Age", Desc).Exec(context.Background()))
+}
+
+func TestQuery_IsImmutable(t *testing.T) {
+	op := NewMagicModelOperatorWithClient(mocks.NewDynamoDBAPI(t), "test-table")
+	var users []TestUser
+	base := op.Query(&users).Limit(5)
+	ordered := base.OrderBy("CreatedAt", Desc).Limit(10)
+
+	require.Equal(t, int32(5), base.limit)
+	require.Empty(t, base.orderBy)
+	require.Equal(t, int32(10), ordered.limit)
+	require.Equal(t, "CreatedAt", ordered.orderBy)
+}
+
+func TestQuery_Where(t *testing.T) {
+	mockDB := mocks.NewDynamoDBAPI(t)
+	mockDB.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return aws.ToString(in.IndexName) == "breed-index" && aws.ToInt32(in.Limit) == 10
+	}), mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+	mockDB.On("Query", mock.Anything, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return in.IndexName == nil && len(in.ExpressionAttributeValues) == 4
+	}), mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+
+	op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+	var labs, puppies []TestDog
+	base := op.Query(&labs).Where("Name", "Buddy")
+
+	require.NoError(t, base.Where("Breed", "Lab").Limit(10).Exec(context.Background()))
+	require.Len(t, base.conditions, 1)
+
+	require.NoError(t, op.Query(&puppies).Where("Age", []int{0, 1}).Exec(context.Background()))
+	require.Nil(t, op.PendingConditions)
+	require.False(t, op.IsWhereV4Chain)
+}
+
+func TestQuery_EmptyValueList(t *testing.T) {
+	op := NewMagicModelOperatorWithClient(mocks.NewDynamoDBAPI(t), "test-table")
+	var dogs []TestDog
+
+	err := op.Query(&dogs).Where("Breed", []string{}).Exec(context.Background())
+	require.ErrorIs(t, err, ErrValidation)
+	require.Contains(t, err.Error(), "no values given for Breed")
+
+	_, err = NewRepo[TestDog](op).Where("Breed", []string{}).List(context.Background())
+	require.ErrorIs(t, err, ErrValidation)
+
+	_, err = op.Page(&dogs, PageOptions{Limit: 10, Conditions: []WhereV4Condition{{FieldName: "Breed"}}})
+	require.ErrorIs(t, err, ErrValidation)
+}
+
+func TestQuery_ConcurrentUse(t *testing.T) {
+	mockDB := mocks.NewDynamoDBAPI(t)
+	mockDB.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{}, nil)
+
+	op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+	base := op.Query(&[]TestDog{}).Where("Breed", "Lab")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(age int) {
+			defer wg.Done()
+			var dogs []TestDog
+			q := op.Query(&dogs).Where("Breed", "Lab").Where("Age", age)
+			assert.NoError(t, q.Exec(context.Background()))
+			assert.Len(t, q.conditions, 2)
+		}(i)
+	}
+	wg.Wait()
+	require.Len(t, base.conditions, 1)
+}
@@ -0,0 +1,121 @@
+package model
+
+import "context"
+
+// Repo is a type-safe view of the items of model T, e.g. model.NewRepo[Dog](mm)
+// T must be a struct type that embeds Model directly
+type Repo[T Modeler] struct {
+	op *Operator
+}
+
+// NewRepo returns a Repo for model T that runs its operations through o
+// Requiring *T to implement Modeler makes the compiler reject pointer types for T, as pointers to
+// pointers have no methods. A T that only embeds Model through another struct still compiles, so
+// every method of its Repo returns the validation error describing it instead
+func NewRepo[T Modeler, PT interface {
+	*T
+	Modeler
+}](o *Operator) *Repo[T] {
+	var item T
+	name, err := ParseModelName(&item)
+	if err == nil {
+		err = ValidateInput(&item, "NewRepo", name)
+	}
+	if err != nil && o.Err == nil {
+		op := *o
+		op.Err = err
+		o = &op
+	}
+	return &Repo[T]{op: o}
+}
+
+// Find loads the item with the given ID
+func (r *Repo[T]) Find(ctx context.Context, id string) (T, error) {
+	var item T
+	err := r.op.WithContext(ctx).Find(&item, id).Err
+	return item, err
+}
+
+// All loads every item of the model that has not been soft deleted
+func (r *Repo[T]) All(ctx context.Context) ([]T, error) {
+	var items []T
+	err := r.op.WithContext(ctx).All(&items).Err
+	return items, err
+}
+
+// Create stores a new item, assigning its ID and timestamps like Operator.Create
+func (r *Repo[T]) Create(ctx context.Context, item *T, opts ...CreateOption) error {
+	return r.op.WithContext(ctx).Create(item, opts...).Err
+}
+
+// Save creates or replaces an item like Operator.Save
+func (r *Repo[T]) Save(ctx context.Context, item *T) error {
+	return r.op.WithContext(ctx).Save(item).Err
+}
+
+// UpdateFields sets several fields of an item in a single call like Operator.UpdateFields
+func (r *Repo[T]) UpdateFields(ctx context.Context, item *T, fields map[string]interface{}) error {
+	return r.op.WithContext(ctx).UpdateFields(item, fields).Err
+}
+
+// Delete removes an item like Operator.Delete
+func (r *Repo[T]) Delete(ctx context.Context, item *T) error {
+	return r.op.WithContext(ctx).Delete(item).Err
+}
+
+// Count returns how many items match every one of conditions like Operator.Count
+func (r *Repo[T]) Count(ctx context.Context, conditions ...Condition) (CountResult, error) {
+	var item T
+	return r.op.WithContext(ctx).Count(&item, conditions...)
+}
+
+// Query starts a typed query over the items of the model
+func (r *Repo[T]) Query() *RepoQuery[T] {
+	return &RepoQuery[T]{q: r.op.Query(&[]T{})}
+}
+
+// Where starts a typed query that only returns items whose field matches value, as in Query.Where
+func (r *Repo[T]) Where(field string, value interface{}) *RepoQuery[T] {
+	return r.Query().Where(field, value)
+}
+
+// RepoQuery is a Query whose results are returned as a slice of T
+// Like Query, each method returns a new RepoQuery so it is safe to share between goroutines
+type RepoQuery[T Modeler] struct {
+	q *Query
+}
+
+// Where adds a condition on field as in Query.Where
+func (rq *RepoQuery[T]) Where(field string, value interface{}) *RepoQuery[T] {
+	return &RepoQuery[T]{q: rq.q.Where(field, value)}
+}
+
+// Filter adds a condition tree as in Query.Filter
+func (rq *RepoQuery[T]) Filter(c Condition) *RepoQuery[T] {
+	return &RepoQuery[T]{q: rq.q.Filter(c)}
+}
+
+// OrderBy sorts the results as in Query.OrderBy
+func (rq *RepoQuery[T]) OrderBy(field string, order SortOrder) *RepoQuery[T] {
+	return &RepoQuery[T]{q: rq.q.OrderBy(field, order)}
+}
+
+// Select only loads the given attributes as in Query.Select
+func (rq *RepoQuery[T]) Select(fields ...string) *RepoQuery[T] {
+	return &RepoQuery[T]{q: rq.q.Select(fields...)}
+}
+
+// Limit caps the number of items returned as in Query.Limit
+func (rq *RepoQuery[T]) Limit(n int32) *RepoQuery[T] {
+	return &RepoQuery[T]{q: rq.q.Limit(n)}
+}
+
+// List runs the query with ctx and returns the matching items
+func (rq *RepoQuery[T]) List(ctx context.Context) ([]T, error) {
+	var items []T
+	// Every run loads into its own slice so concurrent calls do not share results
+	q := *rq.q
+	q.q = &items
+	err := q.Exec(ctx)
+	return items, err
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func dogItems(t *testing.T, dogs ...TestDog) []map[string]types.AttributeValue {
+	items := make([]map[string]types.AttributeValue, len(dogs))
+	for i, dog := range dogs {
+		item, err := attributevalue.MarshalMap(dog)
+		require.NoError(t, err)
+		items[i] = item
+	}
+	return items
+}
+
+func TestRepo(t *testing.T) {
+	ctx := context.Background()
+	buddy := TestDog{Model: Model{ID: "1", Type: "test_dog"}, Name: "Buddy", Breed: "Lab", Age: 3}
+	rex := TestDog{Model: Model{ID: "2", Type: "test_dog"}, Name: "Rex", Breed: "Lab", Age: 5}
+
+	t.Run("find", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("GetItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{Item: dogItems(t, buddy)[0]}, nil)
+
+		dog, err := NewRepo[TestDog](NewMagicModelOperatorWithClient(mockDB, "test-table")).Find(ctx, "1")
+		require.NoError(t, err)
+		require.Equal(t, buddy, dog)
+	})
+
+	t.Run("find_not_found", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("GetItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil)
+
+		_, err := NewRepo[TestDog](NewMagicModelOperatorWithClient(mockDB, "test-table")).Find(ctx, "1")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("Query", ctx, mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{Items: dogItems(t, buddy, rex)}, nil)
+
+		dogs, err := NewRepo[TestDog](NewMagicModelOperatorWithClient(mockDB, "test-table")).All(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []TestDog{buddy, rex}, dogs)
+	})
+
+	t.Run("where_list", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("Query", ctx, indexQuery("breed-index"), mock.Anything).Return(&dynamodb.QueryOutput{Items: dogItems(t, buddy)}, nil).Once()
+		mockDB.On("Query", ctx, indexQuery("breed-index"), mock.Anything).Return(&dynamodb.QueryOutput{Items: dogItems(t, rex)}, nil).Once()
+
+		labs := NewRepo[TestDog](NewMagicModelOperatorWithClient(mockDB, "test-table")).Where("Breed", "Lab")
+		first, err := labs.Where("Age", Gt(1)).List(ctx)
+		require.NoError(t, err)
+		second, err := labs.List(ctx)
+		require.NoError(t, err)
+
+		// Each List call returns its own results
+		require.Equal(t, []TestDog{buddy}, first)
+		require.Equal(t, []TestDog{rex}, second)
+	})
+
+	t.Run("create", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("PutItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.PutItemOutput{}, nil)
+
+		dog := TestDog{Name: "Buddy"}
+		require.NoError(t, NewRepo[TestDog](NewMagicModelOperatorWithClient(mockDB, "test-table")).Create(ctx, &dog))
+		require.NotEmpty(t, dog.ID)
+		require.Equal(t, "test_dog", dog.Type)
+	})
+
+	t.Run("nested_model", func(t *testing.T) {
+		type outerDog struct {
+			TestDog
+		}
+		repo := NewRepo[outerDog](NewMagicModelOperatorWithClient(mocks.NewDynamoDBAPI(t), "test-table"))
+
+		_, err := repo.Find(ctx, "1")
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorContains(t, err, "must embed model.Model")
+		_, err = repo.Where("Breed", "Lab").List(ctx)
+		require.ErrorIs(t, err, ErrValidation)
+		_, err = repo.Count(ctx)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
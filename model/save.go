@@ -2,13 +2,12 @@ package model
 
 import (
 	"context"
-	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/google/uuid"
 	"reflect"
-	"time"
 )
 
 func (o *Operator) Save(q interface{}) *Operator {
@@ -16,44 +15,156 @@ func (o *Operator) Save(q interface{}) *Operator {
 		return o
 	}
 
-	name, err := ParseModelName(q)
+	w, err := prepareSave(o.Context(), q)
 	if err != nil {
 		o.Err = err
 		return o
 	}
 
-	err = ValidateInput(q, "Save", name)
+	payload := reflect.ValueOf(q).Elem()
+	if old := o.snapshots.lookup(payload); old != nil {
+		return o.saveChanges(q, w, old)
+	}
+
+	_, err = o.db.PutItem(o.Context(), &dynamodb.PutItemInput{
+		TableName:                 aws.String(o.tableName),
+		Item:                      w.item,
+		ConditionExpression:       w.expr.Condition(),
+		ExpressionAttributeNames:  w.expr.Names(),
+		ExpressionAttributeValues: w.expr.Values(),
+	})
+
 	if err != nil {
-		o.Err = err
+		if w.conflict != nil && isConditionFailed(err) {
+			o.Err = newOperationError("Save", q, w.conflict)
+			return o
+		}
+		o.Err = newOperationError("Save", q, err)
 		return o
 	}
 
+	w.refresh(o.snapshots)
+	err = w.succeeded(o.Context())
+	if err != nil {
+		o.Err = newOperationError("Save", q, err)
+	}
+	return o
+}
+
+// saveChanges writes the attributes of w that differ from old, the snapshot of the item taken when it
+// was loaded, in a single UpdateItem. Nothing is written when no attribute changed
+// The update is guarded by the item still existing so it cannot create a partial item
+func (o *Operator) saveChanges(q interface{}, w *preparedWrite, old map[string]types.AttributeValue) *Operator {
+	payload := reflect.ValueOf(q).Elem()
+	version, versionName, err := versionField(payload)
+	if err != nil {
+		o.Err = newOperationError("Save", q, err)
+		return o
+	}
+
+	update, changes := diffItems(expression.UpdateBuilder{}, "", old, w.item, "Type", "ID", versionName)
+	if changes == 0 {
+		return o
+	}
+
+	condition := expression.AttributeExists(expression.Name("ID"))
+	conflict := ErrNotFound
+	if version.IsValid() {
+		current := version.Int()
+		update = update.Set(expression.Name(versionName), expression.Value(current+1))
+		condition = condition.And(versionCondition(versionName, current))
+		conflict = ErrVersionConflict
+	}
+	expr, err := expression.NewBuilder().WithCondition(condition).WithUpdate(update).Build()
+	if err != nil {
+		o.Err = newOperationError("Save", q, err)
+		return o
+	}
+
+	_, err = o.db.UpdateItem(o.Context(), &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(o.tableName),
+		Key:                       modelKey(payload),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+	})
+
+	if err != nil {
+		if isConditionFailed(err) {
+			o.Err = newOperationError("Save", q, conflict)
+			return o
+		}
+		o.Err = newOperationError("Save", q, err)
+		return o
+	}
+
+	w.refresh(o.snapshots)
+	err = w.succeeded(o.Context())
+	if err != nil {
+		o.Err = newOperationError("Save", q, err)
+	}
+	return o
+}
+
+// prepareSave validates q, assigns its Type, ID and timestamps if it is new, runs its BeforeSave hook,
+// checks the rules declared in its mm tags and marshals it for a put
+// Versioned models are marshalled with the next version and guarded by a check of the current one
+func prepareSave(ctx context.Context, q interface{}) (*preparedWrite, error) {
+	name, err := ParseModelName(q)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ValidateInput(q, "Save", name)
+	if err != nil {
+		return nil, err
+	}
+
 	payload := reflect.ValueOf(q).Elem()
 
 	id := payload.FieldByName("ID").String()
 	if id == "" {
-		t := time.Now()
+		t := timestamp()
 		payload.FieldByName("Type").SetString(name)
 		payload.FieldByName("ID").SetString(uuid.New().String())
 		payload.FieldByName("CreatedAt").Set(reflect.ValueOf(t))
 		payload.FieldByName("UpdatedAt").Set(reflect.ValueOf(t))
 	}
 
-	av, err := attributevalue.MarshalMap(q)
+	err = callHook(ctx, q, BeforeSaver.BeforeSave)
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during Save operation: %v", err)
-		return o
+		return nil, newOperationError("Save", q, err)
 	}
 
-	_, err = svc.PutItem(context.TODO(), &dynamodb.PutItemInput{
-		TableName: aws.String(dynamoDBTableName),
-		Item:      av,
-	})
+	err = validateModel(payload)
+	if err != nil {
+		return nil, newOperationError("Save", q, err)
+	}
 
+	w := &preparedWrite{after: afterHook(q, AfterSaver.AfterSave)}
+	version, versionName, err := versionField(payload)
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during Save operation: %v", err)
-		return o
+		return nil, newOperationError("Save", q, err)
 	}
+	if version.IsValid() {
+		current := version.Int()
+		w.expr, err = expression.NewBuilder().WithCondition(versionCondition(versionName, current)).Build()
+		if err != nil {
+			return nil, newOperationError("Save", q, err)
+		}
+		w.conflict = ErrVersionConflict
+		w.done = func() { version.SetInt(current + 1) }
 
-	return o
+		// Store the version the item will have once the write succeeds
+		version.SetInt(current + 1)
+		defer version.SetInt(current)
+	}
+
+	w.item, err = marshalItem(q)
+	if err != nil {
+		return nil, newOperationError("Save", q, err)
+	}
+	w.snapshot = func(s *snapshots) { s.store(payload, w.item) }
+	return w, nil
 }
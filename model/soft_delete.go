@@ -2,11 +2,9 @@ package model
 
 import (
 	"context"
-	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"reflect"
 	"time"
 )
@@ -16,44 +14,64 @@ func (o *Operator) SoftDelete(q interface{}) *Operator {
 		return o
 	}
 
-	name, err := ParseModelName(q)
+	w, err := prepareSoftDelete(o.Context(), q)
 	if err != nil {
 		o.Err = err
 		return o
 	}
 
-	err = ValidateInput(q, "SoftDelete", name)
+	_, err = o.db.UpdateItem(o.Context(), &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(o.tableName),
+		Key:                       w.key,
+		ExpressionAttributeNames:  w.expr.Names(),
+		ExpressionAttributeValues: w.expr.Values(),
+		UpdateExpression:          w.expr.Update(),
+	})
+
 	if err != nil {
-		o.Err = err
+		o.Err = newOperationError("SoftDelete", q, err)
 		return o
 	}
 
-	t := time.Now()
-	payload := reflect.ValueOf(q).Elem()
-	update := expression.Set(expression.Name("DeletedAt"), expression.Value(t))
-	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	w.refresh(o.snapshots)
+	err = w.succeeded(o.Context())
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during SoftDelete operation: %v", err)
-		return o
+		o.Err = newOperationError("SoftDelete", q, err)
 	}
+	return o
+}
 
-	//payload.FieldByName("DeletedAt").Set(reflect.ValueOf(t))
-	key := map[string]types.AttributeValue{
-		"ID":   &types.AttributeValueMemberS{Value: payload.FieldByName("ID").String()},
-		"Type": &types.AttributeValueMemberS{Value: payload.FieldByName("Type").String()},
+// prepareSoftDelete validates q, runs its BeforeDelete hook and builds the key and update expression
+// that mark it as deleted
+func prepareSoftDelete(ctx context.Context, q interface{}) (*preparedWrite, error) {
+	name, err := ParseModelName(q)
+	if err != nil {
+		return nil, err
 	}
 
-	_, err = svc.UpdateItem(context.TODO(), &dynamodb.UpdateItemInput{
-		TableName:                 aws.String(dynamoDBTableName),
-		Key:                       key,
-		ExpressionAttributeNames:  expr.Names(),
-		ExpressionAttributeValues: expr.Values(),
-		UpdateExpression:          expr.Update(),
-	})
+	err = ValidateInput(q, "SoftDelete", name)
+	if err != nil {
+		return nil, err
+	}
 
+	err = callHook(ctx, q, BeforeDeleter.BeforeDelete)
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during SoftDelete operation: %v", err)
-		return o
+		return nil, newOperationError("SoftDelete", q, err)
 	}
-	return o
+
+	t := time.Now()
+	payload := reflect.ValueOf(q).Elem()
+	update := expression.Set(expression.Name("DeletedAt"), expression.Value(t))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return nil, newOperationError("SoftDelete", q, err)
+	}
+
+	//payload.FieldByName("DeletedAt").Set(reflect.ValueOf(t))
+	return &preparedWrite{
+		key:      modelKey(payload),
+		expr:     expr,
+		after:    afterHook(q, AfterDeleter.AfterDelete),
+		snapshot: func(s *snapshots) { s.forget(payload) },
+	}, nil
 }
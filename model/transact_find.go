@@ -0,0 +1,100 @@
+package model
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// TransactFind reads several items, possibly of different model Types, as one consistent
+// snapshot using TransactGetItems. Arguments are pairs of a model pointer and the ID to load
+// into it, e.g. TransactFind(&dog, dogID, &owner, ownerID)
+// The returned slice reports for each pair whether the item was found; missing items are
+// left untouched instead of failing the whole read. Found items are loaded as by Find, so the
+// operator's Select projection applies, AfterFind hooks run and dirty tracking snapshots them
+func (o *Operator) TransactFind(targets ...interface{}) ([]bool, error) {
+	if o.Err != nil {
+		return nil, o.Err
+	}
+
+	if len(targets) == 0 || len(targets)%2 != 0 {
+		return nil, newOperationError("TransactFind", nil, validationErrorf("expected pairs of a model pointer and an ID, got %d arguments", len(targets)))
+	}
+
+	count := len(targets) / 2
+	if count > maxTransactionItems {
+		return nil, newOperationError("TransactFind", nil, validationErrorf("%d items exceeds the limit of %d", count, maxTransactionItems))
+	}
+
+	items := make([]types.TransactGetItem, count)
+	for i := 0; i < count; i++ {
+		q := targets[2*i]
+		id, ok := targets[2*i+1].(string)
+		if !ok {
+			return nil, newOperationError("TransactFind", nil, validationErrorf("expected a string ID for item %d, got %T", i, targets[2*i+1]))
+		}
+
+		name, err := ParseModelName(q)
+		if err != nil {
+			return nil, err
+		}
+		err = ValidateInput(q, "TransactFind", name)
+		if err != nil {
+			return nil, err
+		}
+
+		get := &types.Get{
+			TableName: aws.String(o.tableName),
+			Key: map[string]types.AttributeValue{
+				"Type": &types.AttributeValueMemberS{Value: name},
+				"ID":   &types.AttributeValueMemberS{Value: id},
+			},
+		}
+		if len(o.projection) > 0 {
+			meta, err := metaOf(q)
+			if err != nil {
+				return nil, newOperationError("TransactFind", q, err)
+			}
+			expr, err := withProjection(expression.NewBuilder(), meta.fields, o.projection).Build()
+			if err != nil {
+				return nil, newOperationError("TransactFind", q, err)
+			}
+			get.ProjectionExpression = expr.Projection()
+			get.ExpressionAttributeNames = expr.Names()
+		}
+		items[i] = types.TransactGetItem{Get: get}
+	}
+
+	out, err := o.db.TransactGetItems(o.Context(), &dynamodb.TransactGetItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		return nil, newOperationError("TransactFind", nil, err)
+	}
+
+	found := make([]bool, count)
+	var hookErr error
+	for i, response := range out.Responses {
+		if i >= count || response.Item == nil {
+			continue
+		}
+
+		q := targets[2*i]
+		err = attributevalue.UnmarshalMap(response.Item, q)
+		if err != nil {
+			return nil, newOperationError("TransactFind", q, err)
+		}
+		o.track(q)
+		found[i] = true
+
+		// Like the After hooks of a transaction, every AfterFind hook runs and the first error is reported
+		err = callHook(o.Context(), q, AfterFinder.AfterFind)
+		if err != nil && hookErr == nil {
+			hookErr = newOperationError("TransactFind", q, err)
+		}
+	}
+
+	return found, hookErr
+}
@@ -0,0 +1,158 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperator_TransactFind(t *testing.T) {
+	user, _ := attributevalue.MarshalMap(&TestUser{Model: Model{ID: "u1", Type: "test_user"}, Name: "John Doe"})
+
+	tests := []struct {
+		name          string
+		setupMock     func(*mocks.DynamoDBAPI)
+		targets       func(*TestUser, *TestPet) []interface{}
+		expectError   bool
+		errorContains string
+		expectedFound []bool
+	}{
+		{
+			name: "reports_missing_items",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("TransactGetItems", mock.Anything, mock.MatchedBy(func(in *dynamodb.TransactGetItemsInput) bool {
+					petType, ok := in.TransactItems[1].Get.Key["Type"].(*types.AttributeValueMemberS)
+					return len(in.TransactItems) == 2 && ok && petType.Value == "test_pet"
+				}), mock.Anything).Return(&dynamodb.TransactGetItemsOutput{
+					Responses: []types.ItemResponse{{Item: user}, {}},
+				}, nil)
+			},
+			targets: func(u *TestUser, p *TestPet) []interface{} {
+				return []interface{}{u, "u1", p, "p1"}
+			},
+			expectedFound: []bool{true, false},
+		},
+		{
+			name: "query_fails",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("TransactGetItems", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("dynamodb error"))
+			},
+			targets: func(u *TestUser, p *TestPet) []interface{} {
+				return []interface{}{u, "u1"}
+			},
+			expectError:   true,
+			errorContains: "dynamodb error",
+		},
+		{
+			name: "odd_arguments",
+			targets: func(u *TestUser, p *TestPet) []interface{} {
+				return []interface{}{u, "u1", p}
+			},
+			expectError:   true,
+			errorContains: "expected pairs",
+		},
+		{
+			name: "non_string_id",
+			targets: func(u *TestUser, p *TestPet) []interface{} {
+				return []interface{}{u, 1}
+			},
+			expectError:   true,
+			errorContains: "expected a string ID",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			if tc.setupMock != nil {
+				tc.setupMock(mockDB)
+			}
+
+			op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+			user, pet := &TestUser{}, &TestPet{}
+			found, err := op.TransactFind(tc.targets(user, pet)...)
+
+			if tc.expectError {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.errorContains)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedFound, found)
+			require.Equal(t, "John Doe", user.Name)
+			require.Empty(t, pet.ID)
+		})
+	}
+}
+
+func TestOperator_TransactFindLoadsLikeFind(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hookCtxKey{}, "req")
+
+	t.Run("runs_after_find_hooks", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("TransactGetItems", mock.Anything, mock.Anything, mock.Anything).Return(&dynamodb.TransactGetItemsOutput{
+			Responses: []types.ItemResponse{{Item: storedItem(t, TestHookedDog{Model: Model{ID: "1", Type: "test_hooked_dog"}, Name: "Buddy"})}},
+		}, nil).Once()
+
+		var dog TestHookedDog
+		_, err := NewMagicModelOperatorWithClient(mockDB, "test-table").WithContext(ctx).TransactFind(&dog, "1")
+		require.NoError(t, err)
+		require.Equal(t, []string{"AfterFind:req"}, dog.Calls)
+	})
+
+	t.Run("reports_after_find_errors", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("TransactGetItems", mock.Anything, mock.Anything, mock.Anything).Return(&dynamodb.TransactGetItemsOutput{
+			Responses: []types.ItemResponse{{Item: storedItem(t, TestHookedDog{Model: Model{ID: "1", Type: "test_hooked_dog"}, Name: "Buddy"})}},
+		}, nil).Once()
+
+		dog := TestHookedDog{Fail: "AfterFind"}
+		found, err := NewMagicModelOperatorWithClient(mockDB, "test-table").TransactFind(&dog, "1")
+		require.ErrorContains(t, err, "AfterFind failed")
+		require.Equal(t, []bool{true}, found)
+	})
+
+	t.Run("tracks_found_items", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("TransactGetItems", mock.Anything, mock.Anything, mock.Anything).Return(&dynamodb.TransactGetItemsOutput{
+			Responses: []types.ItemResponse{{Item: storedItem(t, TestDog{Model: Model{ID: "1", Type: "test_dog"}, Name: "Buddy", Age: 3})}},
+		}, nil).Once()
+		mockDB.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+			names := updatedNames(in)
+			return len(names) == 1 && names["Age"]
+		}), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+		op := NewMagicModelOperatorWithClient(mockDB, "test-table").WithDirtyTracking()
+		var dog TestDog
+		_, err := op.TransactFind(&dog, "1")
+		require.NoError(t, err)
+
+		require.NoError(t, op.Save(&dog).Err)
+		dog.Age = 4
+		require.NoError(t, op.Save(&dog).Err)
+	})
+
+	t.Run("applies_the_projection", func(t *testing.T) {
+		mockDB := mocks.NewDynamoDBAPI(t)
+		mockDB.On("TransactGetItems", mock.Anything, mock.MatchedBy(func(in *dynamodb.TransactGetItemsInput) bool {
+			get := in.TransactItems[0].Get
+			return reflect.DeepEqual(projectedPaths(get.ProjectionExpression, get.ExpressionAttributeNames), []string{"Name"})
+		}), mock.Anything).Return(&dynamodb.TransactGetItemsOutput{
+			Responses: []types.ItemResponse{{Item: storedItem(t, TestDog{Name: "Buddy"})}},
+		}, nil).Once()
+
+		var dog TestDog
+		_, err := NewMagicModelOperatorWithClient(mockDB, "test-table").Select("Name").TransactFind(&dog, "1")
+		require.NoError(t, err)
+		require.Equal(t, "Buddy", dog.Name)
+	})
+}
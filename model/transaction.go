@@ -0,0 +1,294 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+	"strings"
+)
+
+// maxTransactionItems is the most items DynamoDB accepts in a single TransactWriteItems call
+const maxTransactionItems = 100
+
+// Tx collects writes that Operator.Transaction commits atomically
+// Its methods mirror the Operator's, and the first error is kept in Err
+type Tx struct {
+	Err       error
+	tableName string
+	ctx       context.Context
+	items     []types.TransactWriteItem
+	targets   []txTarget
+}
+
+// txTarget remembers which model each transaction item belongs to for error reporting,
+// along with its prepared write so deferred model changes can be applied after the commit
+type txTarget struct {
+	operation string
+	modelType string
+	id        string
+	write     *preparedWrite
+}
+
+// TransactionItemError describes why DynamoDB rejected a single item of a canceled transaction
+type TransactionItemError struct {
+	Index     int
+	Operation string
+	Type      string
+	ID        string
+	Code      string
+	Message   string
+	err       error
+}
+
+func (e *TransactionItemError) Error() string {
+	msg := fmt.Sprintf("transaction item %d (%s %s %s) failed: %s", e.Index, e.Operation, e.Type, e.ID, e.Code)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Unwrap returns ErrVersionConflict when a versioned model failed its version check,
+// ErrAlreadyExists when a created item's ID was already taken, ErrConditionFailed for any
+// other failed condition and ErrThrottled when the item was throttled
+func (e *TransactionItemError) Unwrap() error {
+	return e.err
+}
+
+// TransactionError is returned when DynamoDB cancels a transaction
+// Items holds an entry for every item that caused the cancellation
+type TransactionError struct {
+	Items []*TransactionItemError
+	Err   error
+}
+
+func (e *TransactionError) Error() string {
+	reasons := make([]string, len(e.Items))
+	for i, item := range e.Items {
+		reasons[i] = item.Error()
+	}
+	return fmt.Sprintf("encountered an error during Transaction operation: transaction canceled: %s", strings.Join(reasons, "; "))
+}
+
+func (e *TransactionError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Items)+1)
+	for _, item := range e.Items {
+		errs = append(errs, item)
+	}
+	return append(errs, e.Err)
+}
+
+// Transaction runs fn to collect writes and commits them in a single TransactWriteItems call
+// Either every write succeeds or none are applied. If fn returns an error nothing is written
+func (o *Operator) Transaction(fn func(tx *Tx) error) *Operator {
+	if o.Err != nil {
+		return o
+	}
+
+	tx := &Tx{tableName: o.tableName, ctx: o.Context()}
+	if err := fn(tx); err != nil {
+		o.Err = newOperationError("Transaction", nil, err)
+		return o
+	}
+	if tx.Err != nil {
+		o.Err = tx.Err
+		return o
+	}
+
+	if len(tx.items) == 0 {
+		return o
+	}
+	if len(tx.items) > maxTransactionItems {
+		o.Err = newOperationError("Transaction", nil, validationErrorf("%d items exceeds the limit of %d", len(tx.items), maxTransactionItems))
+		return o
+	}
+
+	_, err := o.db.TransactWriteItems(o.Context(), &dynamodb.TransactWriteItemsInput{
+		TransactItems: tx.items,
+	})
+	if err != nil {
+		var canceled *types.TransactionCanceledException
+		if errors.As(err, &canceled) {
+			o.Err = newTransactionError(tx.targets, canceled)
+			return o
+		}
+		o.Err = newOperationError("Transaction", nil, err)
+		return o
+	}
+
+	// Every write went through, so all After hooks run and the first error is reported
+	for _, target := range tx.targets {
+		target.write.refresh(o.snapshots)
+		err := target.write.succeeded(o.Context())
+		if err != nil && o.Err == nil {
+			o.Err = &OperationError{Op: target.operation, Type: target.modelType, ID: target.id, Err: err}
+		}
+	}
+	return o
+}
+
+// Create adds a put of a new item to the transaction, assigning its ID and timestamps like Operator.Create
+func (tx *Tx) Create(q interface{}, opts ...CreateOption) *Tx {
+	if tx.Err != nil {
+		return tx
+	}
+
+	w, err := prepareCreate(tx.ctx, q, opts...)
+	if err != nil {
+		tx.Err = err
+		return tx
+	}
+
+	return tx.add("Create", q, w, types.TransactWriteItem{
+		Put: &types.Put{
+			TableName:                 aws.String(tx.tableName),
+			Item:                      w.item,
+			ConditionExpression:       w.expr.Condition(),
+			ExpressionAttributeNames:  w.expr.Names(),
+			ExpressionAttributeValues: w.expr.Values(),
+		},
+	})
+}
+
+// Save adds a put of the whole item to the transaction like Operator.Save
+func (tx *Tx) Save(q interface{}) *Tx {
+	if tx.Err != nil {
+		return tx
+	}
+
+	w, err := prepareSave(tx.ctx, q)
+	if err != nil {
+		tx.Err = err
+		return tx
+	}
+
+	return tx.add("Save", q, w, types.TransactWriteItem{
+		Put: &types.Put{
+			TableName:                 aws.String(tx.tableName),
+			Item:                      w.item,
+			ConditionExpression:       w.expr.Condition(),
+			ExpressionAttributeNames:  w.expr.Names(),
+			ExpressionAttributeValues: w.expr.Values(),
+		},
+	})
+}
+
+// Update adds a single attribute update to the transaction like Operator.Update
+func (tx *Tx) Update(q interface{}, k string, v interface{}) *Tx {
+	return tx.updateFields("Update", q, map[string]interface{}{k: v})
+}
+
+// UpdateFields adds an update of several attributes to the transaction like Operator.UpdateFields
+func (tx *Tx) UpdateFields(q interface{}, fields map[string]interface{}) *Tx {
+	return tx.updateFields("UpdateFields", q, fields)
+}
+
+func (tx *Tx) updateFields(operation string, q interface{}, fields map[string]interface{}) *Tx {
+	if tx.Err != nil {
+		return tx
+	}
+
+	w, err := prepareUpdate(tx.ctx, operation, q, fields)
+	if err != nil {
+		tx.Err = err
+		return tx
+	}
+
+	return tx.add(operation, q, w, types.TransactWriteItem{
+		Update: &types.Update{
+			TableName:                 aws.String(tx.tableName),
+			Key:                       w.key,
+			ConditionExpression:       w.expr.Condition(),
+			ExpressionAttributeNames:  w.expr.Names(),
+			ExpressionAttributeValues: w.expr.Values(),
+			UpdateExpression:          w.expr.Update(),
+		},
+	})
+}
+
+// Delete adds a delete of the item to the transaction like Operator.Delete
+func (tx *Tx) Delete(q interface{}) *Tx {
+	if tx.Err != nil {
+		return tx
+	}
+
+	w, err := prepareDelete(tx.ctx, q)
+	if err != nil {
+		tx.Err = err
+		return tx
+	}
+
+	return tx.add("Delete", q, w, types.TransactWriteItem{
+		Delete: &types.Delete{TableName: aws.String(tx.tableName), Key: w.key},
+	})
+}
+
+// SoftDelete adds a soft delete of the item to the transaction like Operator.SoftDelete
+func (tx *Tx) SoftDelete(q interface{}) *Tx {
+	if tx.Err != nil {
+		return tx
+	}
+
+	w, err := prepareSoftDelete(tx.ctx, q)
+	if err != nil {
+		tx.Err = err
+		return tx
+	}
+
+	return tx.add("SoftDelete", q, w, types.TransactWriteItem{
+		Update: &types.Update{
+			TableName:                 aws.String(tx.tableName),
+			Key:                       w.key,
+			ExpressionAttributeNames:  w.expr.Names(),
+			ExpressionAttributeValues: w.expr.Values(),
+			UpdateExpression:          w.expr.Update(),
+		},
+	})
+}
+
+// add appends a write to the transaction along with the model it targets
+func (tx *Tx) add(operation string, q interface{}, w *preparedWrite, item types.TransactWriteItem) *Tx {
+	payload := reflect.ValueOf(q).Elem()
+	tx.items = append(tx.items, item)
+	tx.targets = append(tx.targets, txTarget{
+		operation: operation,
+		modelType: payload.FieldByName("Type").String(),
+		id:        payload.FieldByName("ID").String(),
+		write:     w,
+	})
+	return tx
+}
+
+// newTransactionError matches DynamoDB's cancellation reasons to the items that caused them
+func newTransactionError(targets []txTarget, canceled *types.TransactionCanceledException) *TransactionError {
+	txErr := &TransactionError{Err: canceled}
+	for i, reason := range canceled.CancellationReasons {
+		code := aws.ToString(reason.Code)
+		if code == "" || code == "None" || i >= len(targets) {
+			continue
+		}
+		itemErr := &TransactionItemError{
+			Index:     i,
+			Operation: targets[i].operation,
+			Type:      targets[i].modelType,
+			ID:        targets[i].id,
+			Code:      code,
+			Message:   aws.ToString(reason.Message),
+		}
+		switch code {
+		case "ConditionalCheckFailed":
+			itemErr.err = ErrConditionFailed
+			if targets[i].write.conflict != nil {
+				itemErr.err = targets[i].write.conflict
+			}
+		case "ThrottlingError", "ProvisionedThroughputExceeded":
+			itemErr.err = ErrThrottled
+		}
+		txErr.Items = append(txErr.Items, itemErr)
+	}
+	return txErr
+}
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperator_Transaction(t *testing.T) {
+	existing := func(id string) *TestUser {
+		return &TestUser{Model: Model{ID: id, Type: "test_user"}, Name: "Existing"}
+	}
+
+	tests := []struct {
+		name          string
+		setupMock     func(*mocks.DynamoDBAPI)
+		fn            func(*Tx) error
+		expectError   bool
+		errorContains string
+		check         func(*testing.T, error)
+	}{
+		{
+			name: "success",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("TransactWriteItems", mock.Anything, mock.MatchedBy(func(in *dynamodb.TransactWriteItemsInput) bool {
+					return len(in.TransactItems) == 3 &&
+						in.TransactItems[0].Put != nil &&
+						in.TransactItems[1].Update != nil &&
+						in.TransactItems[2].Delete != nil &&
+						aws.ToString(in.TransactItems[2].Delete.TableName) == "test-table"
+				}), mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil)
+			},
+			fn: func(tx *Tx) error {
+				order := &TestUser{Name: "New"}
+				tx.Create(order)
+				tx.Update(existing("2"), "Age", 4)
+				tx.Delete(existing("3"))
+				if order.ID == "" || order.CreatedAt.IsZero() {
+					return errors.New("create did not assign an ID and timestamps")
+				}
+				return nil
+			},
+		},
+		{
+			name: "canceled_reports_items",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("TransactWriteItems", mock.Anything, mock.Anything, mock.Anything).Return(nil, &types.TransactionCanceledException{
+					Message: aws.String("Transaction cancelled"),
+					CancellationReasons: []types.CancellationReason{
+						{Code: aws.String("None")},
+						{Code: aws.String("ConditionalCheckFailed"), Message: aws.String("The conditional request failed")},
+					},
+				})
+			},
+			fn: func(tx *Tx) error {
+				tx.Save(existing("1")).Delete(existing("2"))
+				return nil
+			},
+			expectError:   true,
+			errorContains: "ConditionalCheckFailed",
+			check: func(t *testing.T, err error) {
+				var txErr *TransactionError
+				require.True(t, errors.As(err, &txErr))
+				require.Len(t, txErr.Items, 1)
+
+				var itemErr *TransactionItemError
+				require.True(t, errors.As(err, &itemErr))
+				require.Equal(t, 1, itemErr.Index)
+				require.Equal(t, "Delete", itemErr.Operation)
+				require.Equal(t, "test_user", itemErr.Type)
+				require.Equal(t, "2", itemErr.ID)
+			},
+		},
+		{
+			name: "create_with_taken_id",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("TransactWriteItems", mock.Anything, mock.Anything, mock.Anything).Return(nil, &types.TransactionCanceledException{
+					CancellationReasons: []types.CancellationReason{{Code: aws.String("ConditionalCheckFailed")}},
+				})
+			},
+			fn: func(tx *Tx) error {
+				tx.Create(&TestUser{Name: "John Doe"}, WithID("taken"))
+				return nil
+			},
+			expectError:   true,
+			errorContains: "taken",
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrAlreadyExists)
+			},
+		},
+		{
+			name: "callback_error_aborts",
+			fn: func(tx *Tx) error {
+				tx.Delete(existing("1"))
+				return errors.New("out of stock")
+			},
+			expectError:   true,
+			errorContains: "out of stock",
+		},
+		{
+			name: "invalid_item_aborts",
+			fn: func(tx *Tx) error {
+				tx.Create(&struct{ Name string }{})
+				tx.Delete(existing("1"))
+				return nil
+			},
+			expectError:   true,
+			errorContains: "unnamed struct",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			if tc.setupMock != nil {
+				tc.setupMock(mockDB)
+			}
+
+			op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+			result := op.Transaction(tc.fn)
+
+			if tc.expectError {
+				require.Error(t, result.Err)
+				require.Contains(t, result.Err.Error(), tc.errorContains)
+				if tc.check != nil {
+					tc.check(t, result.Err)
+				}
+				return
+			}
+			require.NoError(t, result.Err)
+		})
+	}
+}
@@ -2,58 +2,236 @@ package model
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"reflect"
+	"sort"
 )
 
+// Update sets a single field of the item q points to, e.g. mm.Update(&dog, "Age", 4)
+// It behaves like UpdateFields with one field
 func (o *Operator) Update(q interface{}, k string, v interface{}) *Operator {
+	return o.updateFields("Update", q, map[string]interface{}{k: v})
+}
+
+// UpdateFields sets several fields of the item q points to in a single UpdateItem call,
+// e.g. mm.UpdateFields(&dog, map[string]interface{}{"Age": 4, "Status": "adopted"})
+// Field names and value types are checked against the struct before anything is sent, the new
+// values are applied to q and UpdatedAt is set to the current time
+func (o *Operator) UpdateFields(q interface{}, fields map[string]interface{}) *Operator {
+	return o.updateFields("UpdateFields", q, fields)
+}
+
+func (o *Operator) updateFields(operation string, q interface{}, fields map[string]interface{}) *Operator {
 	if o.Err != nil {
 		return o
 	}
 
-	name, err := ParseModelName(q)
+	w, err := prepareUpdate(o.Context(), operation, q, fields)
 	if err != nil {
 		o.Err = err
 		return o
 	}
 
-	err = ValidateInput(q, "Update", name)
+	_, err = o.db.UpdateItem(o.Context(), &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(o.tableName),
+		Key:                       w.key,
+		ConditionExpression:       w.expr.Condition(),
+		ExpressionAttributeNames:  w.expr.Names(),
+		ExpressionAttributeValues: w.expr.Values(),
+		UpdateExpression:          w.expr.Update(),
+		ReturnValues:              types.ReturnValueUpdatedNew,
+	})
+
 	if err != nil {
-		o.Err = err
+		if w.conflict != nil && isConditionFailed(err) {
+			o.Err = newOperationError(operation, q, w.conflict)
+			return o
+		}
+		o.Err = newOperationError(operation, q, err)
 		return o
 	}
 
+	w.refresh(o.snapshots)
+	err = w.succeeded(o.Context())
+	if err != nil {
+		o.Err = newOperationError(operation, q, err)
+	}
+	return o
+}
+
+// prepareUpdate validates q and the new field values, applies them to q along with a new UpdatedAt,
+// runs its BeforeUpdate hook, checks the fields against the rules in their mm tags and builds the key
+// and update expression from the values the hook left in the fields
+// Versioned models also have their version incremented, guarded by a check of the current one
+func prepareUpdate(ctx context.Context, operation string, q interface{}, fields map[string]interface{}) (*preparedWrite, error) {
+	name, err := ParseModelName(q)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ValidateInput(q, operation, name)
+	if err != nil {
+		return nil, err
+	}
+
 	payload := reflect.ValueOf(q).Elem()
-	update := expression.Set(expression.Name(k), expression.Value(v))
-	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	meta, err := metaFor(payload.Type())
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during Update operation: %v", err)
-		return o
+		return nil, newOperationError(operation, q, err)
+	}
+
+	if len(fields) == 0 {
+		return nil, newOperationError(operation, q, validationErrorf("no fields to update"))
 	}
+	names := make([]string, 0, len(fields))
+	values := make(map[string]reflect.Value, len(fields))
+	for k, v := range fields {
+		value, err := fieldValue(payload, meta, k, v)
+		if err != nil {
+			return nil, newOperationError(operation, q, err)
+		}
+		names = append(names, k)
+		values[k] = value
+	}
+	// Sorted names keep the update expression the same from one call to the next
+	sort.Strings(names)
 
-	payload.FieldByName(k).Set(reflect.ValueOf(v))
+	for _, k := range names {
+		payload.FieldByName(k).Set(values[k])
+	}
+	payload.FieldByName("UpdatedAt").Set(reflect.ValueOf(timestamp()))
 
-	key := map[string]types.AttributeValue{
-		"ID":   &types.AttributeValueMemberS{Value: payload.FieldByName("ID").String()},
-		"Type": &types.AttributeValueMemberS{Value: payload.FieldByName("Type").String()},
+	err = callHook(ctx, q, BeforeUpdater.BeforeUpdate)
+	if err != nil {
+		return nil, newOperationError(operation, q, err)
 	}
 
-	_, err = svc.UpdateItem(context.TODO(), &dynamodb.UpdateItemInput{
-		TableName:                 aws.String(dynamoDBTableName),
-		Key:                       key,
-		ExpressionAttributeNames:  expr.Names(),
-		ExpressionAttributeValues: expr.Values(),
-		UpdateExpression:          expr.Update(),
-		ReturnValues:              types.ReturnValueUpdatedNew,
-	})
+	validationErr := &ValidationError{}
+	for _, k := range names {
+		var fieldErr *ValidationError
+		if err := validateField(payload, k); errors.As(err, &fieldErr) {
+			validationErr.Fields = append(validationErr.Fields, fieldErr.Fields...)
+		} else if err != nil {
+			return nil, newOperationError(operation, q, err)
+		}
+	}
+	if len(validationErr.Fields) > 0 {
+		return nil, newOperationError(operation, q, validationErr)
+	}
+
+	w := &preparedWrite{key: modelKey(payload), after: afterHook(q, AfterUpdater.AfterUpdate)}
+	update := expression.Set(expression.Name("UpdatedAt"), expression.Value(timestampValue(payload.FieldByName("UpdatedAt").Interface())))
+	attributes := []string{"UpdatedAt"}
+	for _, k := range names {
+		field := payload.FieldByName(k)
+		attributes = append(attributes, meta.fields.attribute(k))
+		// DynamoDB rejects empty index keys, so clearing an indexed field removes the attribute instead
+		attribute := expression.NameNoDotSplit(meta.fields.attribute(k))
+		if meta.isIndexed(k) && isEmptyKey(field) {
+			update = update.Remove(attribute)
+			continue
+		}
+		update = update.Set(attribute, expression.Value(field.Interface()))
+	}
+	builder := expression.NewBuilder()
 
+	version, versionName, err := versionField(payload)
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during Update operation: %v", err)
-		return o
+		return nil, newOperationError(operation, q, err)
 	}
-	return o
+	if version.IsValid() {
+		current := version.Int()
+		update = update.Set(expression.Name(versionName), expression.Value(current+1))
+		builder = builder.WithCondition(versionCondition(versionName, current))
+		w.conflict = ErrVersionConflict
+		w.done = func() { version.SetInt(current + 1) }
+		attributes = append(attributes, versionName)
+
+		// Snapshot the version the item will have once the write succeeds
+		version.SetInt(current + 1)
+		defer version.SetInt(current)
+	}
+
+	w.expr, err = builder.WithUpdate(update).Build()
+	if err != nil {
+		return nil, newOperationError(operation, q, err)
+	}
+
+	// Only the updated attributes are known to be stored, so they replace those of an existing snapshot
+	item, err := marshalItem(q)
+	if err != nil {
+		return nil, newOperationError(operation, q, err)
+	}
+	w.snapshot = func(s *snapshots) { s.update(payload, item, attributes) }
+
+	return w, nil
+}
+
+// readOnlyFields are managed by magicmodel and cannot be set by an update
+var readOnlyFields = map[string]bool{"ID": true, "Type": true, "UpdatedAt": true}
+
+// fieldValue checks that the model held by payload has an exported field k that v can be stored in,
+// and returns v as a value of the field's type. Numbers are converted when no precision is lost
+func fieldValue(payload reflect.Value, meta *modelMeta, k string, v interface{}) (reflect.Value, error) {
+	field, err := updatableField(payload, meta, k)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return convertValue("field "+k, field.Type, v)
+}
+
+// updatableField returns the exported field k of the model held by payload, unless magicmodel manages it
+func updatableField(payload reflect.Value, meta *modelMeta, k string) (reflect.StructField, error) {
+	field, ok := payload.Type().FieldByName(k)
+	if !ok || !field.IsExported() {
+		return reflect.StructField{}, validationErrorf("field %s does not exist on %s", k, payload.Type().Name())
+	}
+	if readOnlyFields[k] || meta.versionName == k {
+		return reflect.StructField{}, validationErrorf("field %s is managed by magicmodel and cannot be updated", k)
+	}
+	return field, nil
+}
+
+// convertValue returns v as a value of type t, converting numbers when no precision is lost
+// label names what is being set in errors, e.g. "field Age"
+func convertValue(label string, t reflect.Type, v interface{}) (reflect.Value, error) {
+	if v == nil {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+			return reflect.Zero(t), nil
+		}
+		return reflect.Value{}, validationErrorf("cannot set %s of type %s to nil", label, t)
+	}
+
+	value := reflect.ValueOf(v)
+	if value.Type().AssignableTo(t) {
+		return value, nil
+	}
+	if isNumberKind(value.Kind()) && isNumberKind(t.Kind()) {
+		converted := value.Convert(t)
+		if fitsNumber(value, converted) {
+			return converted, nil
+		}
+		return reflect.Value{}, validationErrorf("value %v does not fit %s of type %s", v, label, t)
+	}
+	return reflect.Value{}, validationErrorf("cannot set %s of type %s to a %T", label, t, v)
+}
+
+// fitsNumber reports whether converting the number value to converted lost nothing
+// A round trip catches truncated fractions and overflow, and the signs catch integers that wrapped
+func fitsNumber(value, converted reflect.Value) bool {
+	if !converted.Convert(value.Type()).Equal(value) {
+		return false
+	}
+	if value.CanInt() && converted.CanUint() {
+		return value.Int() >= 0
+	}
+	if value.CanUint() && converted.CanInt() {
+		return converted.Int() >= 0
+	}
+	return true
 }
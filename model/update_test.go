@@ -0,0 +1,145 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// updatedNames returns the attribute names an update expression refers to
+func updatedNames(in *dynamodb.UpdateItemInput) map[string]bool {
+	names := map[string]bool{}
+	for placeholder, name := range in.ExpressionAttributeNames {
+		if strings.Contains(aws.ToString(in.UpdateExpression), placeholder) {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+func TestOperator_UpdateFields(t *testing.T) {
+	loaded := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		fields    map[string]interface{}
+		update    func(*Operator, *TestDog, map[string]interface{}) *Operator
+		setupMock func(*mocks.DynamoDBAPI)
+		expectErr string
+		expectDog TestDog
+	}{
+		{
+			name:   "sets_fields_in_one_call",
+			fields: map[string]interface{}{"Age": 4, "Name": "Rex"},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					names := updatedNames(in)
+					return len(names) == 3 && names["Age"] && names["Name"] && names["UpdatedAt"] &&
+						strings.HasPrefix(aws.ToString(in.UpdateExpression), "SET")
+				}), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+			},
+			expectDog: TestDog{Name: "Rex", Breed: "Lab", Age: 4},
+		},
+		{
+			name:   "converts_numbers",
+			fields: map[string]interface{}{"Age": int64(5)},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+			},
+			expectDog: TestDog{Name: "Buddy", Breed: "Lab", Age: 5},
+		},
+		{
+			name:   "removes_empty_index_key",
+			fields: map[string]interface{}{"Breed": ""},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					return strings.Contains(aws.ToString(in.UpdateExpression), "REMOVE")
+				}), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+			},
+			expectDog: TestDog{Name: "Buddy", Age: 3},
+		},
+		{
+			name:      "unknown_field",
+			fields:    map[string]interface{}{"Age": 4, "Color": "brown"},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			expectErr: "field Color does not exist on TestDog",
+		},
+		{
+			name:      "mismatched_type",
+			fields:    map[string]interface{}{"Name": "Rex", "Age": "four"},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			expectErr: "cannot set field Age of type int to a string",
+		},
+		{
+			name:      "lossy_number",
+			fields:    map[string]interface{}{"Age": 4.5},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			expectErr: "value 4.5 does not fit field Age of type int",
+		},
+		{
+			name:      "nil_value",
+			fields:    map[string]interface{}{"Age": nil},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			expectErr: "cannot set field Age of type int to nil",
+		},
+		{
+			name:      "read_only_field",
+			fields:    map[string]interface{}{"ID": "2"},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			expectErr: "field ID is managed by magicmodel",
+		},
+		{
+			name:      "no_fields",
+			fields:    map[string]interface{}{},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			expectErr: "no fields to update",
+		},
+		{
+			name:   "update_mismatched_type_does_not_panic",
+			fields: map[string]interface{}{"Age": "four"},
+			update: func(op *Operator, dog *TestDog, fields map[string]interface{}) *Operator {
+				return op.Update(dog, "Age", fields["Age"])
+			},
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			expectErr: "encountered an error during Update operation: cannot set field Age of type int to a string",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			tc.setupMock(mockDB)
+
+			dog := &TestDog{Model: Model{ID: "1", Type: "test_dog", UpdatedAt: loaded}, Name: "Buddy", Breed: "Lab", Age: 3}
+			op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+			var result *Operator
+			if tc.update != nil {
+				result = tc.update(op, dog, tc.fields)
+			} else {
+				result = op.UpdateFields(dog, tc.fields)
+			}
+
+			if tc.expectErr != "" {
+				require.ErrorIs(t, result.Err, ErrValidation)
+				require.Contains(t, result.Err.Error(), tc.expectErr)
+				// Nothing is applied when any field is rejected
+				require.Equal(t, "Buddy", dog.Name)
+				require.Equal(t, 3, dog.Age)
+				require.Equal(t, loaded, dog.UpdatedAt)
+				return
+			}
+
+			require.NoError(t, result.Err)
+			require.Equal(t, tc.expectDog.Name, dog.Name)
+			require.Equal(t, tc.expectDog.Breed, dog.Breed)
+			require.Equal(t, tc.expectDog.Age, dog.Age)
+			require.True(t, dog.UpdatedAt.After(loaded))
+		})
+	}
+}
@@ -1,10 +1,8 @@
 package model
 
 import (
-	"context"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/stoewer/go-strcase"
@@ -60,16 +58,31 @@ func ParseModelName(q interface{}) (string, error) {
 	}
 
 	if t.Kind() != reflect.Struct {
-		return "", fmt.Errorf("expected a struct or slice of structs, got %s", t.Kind())
+		return "", validationErrorf("expected a struct or slice of structs, got %s", t.Kind())
 	}
 
 	if t.Name() == "" {
-		return "unnamed_struct", fmt.Errorf("cannot use an unnamed struct")
+		return "unnamed_struct", validationErrorf("cannot use an unnamed struct")
 	}
 
 	return strcase.SnakeCase(t.Name()), nil
 }
 
+// lookupField finds the field at path in the struct value holds, where path may name fields by their
+// Go names or by the attributes they are stored as, e.g. a field tagged dynamodbav:"name"
+func lookupField(value reflect.Value, path string) (reflect.Value, bool) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}, false
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	return GetFieldValue(value, fieldsOf(value.Type()).fieldPath(path))
+}
+
 func GetFieldValue(value reflect.Value, fieldPath string) (reflect.Value, bool) {
 	fields := strings.Split(fieldPath, ".")
 
@@ -93,12 +106,12 @@ func GetFieldValue(value reflect.Value, fieldPath string) (reflect.Value, bool)
 func ValidateInput(q interface{}, operation, structName string) error {
 	val := reflect.ValueOf(q)
 	if val.Kind() != reflect.Ptr || val.IsNil() {
-		return fmt.Errorf("the %s operation encountered an error: expected a non-nil pointer to a struct, got %T", operation, q)
+		return validationErrorf("the %s operation encountered an error: expected a non-nil pointer to a struct, got %T", operation, q)
 	}
 
 	checkPayload := val.Elem()
 	if checkPayload.Kind() != reflect.Struct {
-		return fmt.Errorf("the %s operation encountered an error: expected a pointer to a struct, got pointer to %s", operation, checkPayload.Kind())
+		return validationErrorf("the %s operation encountered an error: expected a pointer to a struct, got pointer to %s", operation, checkPayload.Kind())
 	}
 
 	modelType := reflect.TypeOf((*Model)(nil)).Elem()
@@ -113,7 +126,7 @@ func ValidateInput(q interface{}, operation, structName string) error {
 	}
 
 	if !hasModel {
-		return fmt.Errorf(`the %s operation encountered an error: struct %s must embed model.Model (e.g., model.Model `, operation, structName+"`yaml:\",inline\"`"+`)`)
+		return validationErrorf(`the %s operation encountered an error: struct %s must embed model.Model (e.g., model.Model `, operation, structName+"`yaml:\",inline\"`"+`)`)
 	}
 	return nil
 }
@@ -121,12 +134,12 @@ func ValidateInput(q interface{}, operation, structName string) error {
 func validateInputSlice(q interface{}, operation, structName string) error {
 	val := reflect.ValueOf(q)
 	if val.Kind() != reflect.Ptr || val.IsNil() {
-		return fmt.Errorf("the %s operation encountered an error: expected a non-nil pointer to a slice, got %T", operation, q)
+		return validationErrorf("the %s operation encountered an error: expected a non-nil pointer to a slice, got %T", operation, q)
 	}
 
 	sliceVal := val.Elem()
 	if sliceVal.Kind() != reflect.Slice {
-		return fmt.Errorf("the %s operation encountered an error: expected a pointer to a slice, got pointer to %s", operation, sliceVal.Kind())
+		return validationErrorf("the %s operation encountered an error: expected a pointer to a slice, got pointer to %s", operation, sliceVal.Kind())
 	}
 
 	elemType := sliceVal.Type().Elem()
@@ -134,7 +147,7 @@ func validateInputSlice(q interface{}, operation, structName string) error {
 		elemType = elemType.Elem() // unwrap *T if slice is []*T
 	}
 	if elemType.Kind() != reflect.Struct {
-		return fmt.Errorf("the %s operation encountered an error: slice elements must be structs, got %s", operation, elemType.Kind())
+		return validationErrorf("the %s operation encountered an error: slice elements must be structs, got %s", operation, elemType.Kind())
 	}
 
 	modelType := reflect.TypeOf((*Model)(nil)).Elem()
@@ -149,7 +162,7 @@ func validateInputSlice(q interface{}, operation, structName string) error {
 	}
 
 	if !hasModel {
-		return fmt.Errorf(
+		return validationErrorf(
 			"the %s operation encountered an error: struct %s must embed model.Model (e.g., model.Model `yaml:\",inline\"`)",
 			operation, structName)
 	}
@@ -160,6 +173,11 @@ func validateInputSlice(q interface{}, operation, structName string) error {
 // compareValues compares two values and returns true if they match
 // Handles various types of comparisons including pointers, direct equality, and string representation
 func compareValues(fieldValue reflect.Value, compareValue interface{}) bool {
+	// Comparisons such as Gt or BeginsWith evaluate themselves
+	if comparison, ok := compareValue.(Comparison); ok {
+		return comparison.match(fieldValue)
+	}
+
 	// Handle nil pointer values for booleans
 	if fieldValue.Kind() == reflect.Ptr && !fieldValue.IsNil() && fieldValue.Elem().Kind() == reflect.Bool {
 		// Compare with the dereferenced value
@@ -182,6 +200,7 @@ func compareValues(fieldValue reflect.Value, compareValue interface{}) bool {
 func filterSliceByField(slice reflect.Value, fieldName string, compareValue interface{}) reflect.Value {
 	// Create a new slice of the same type
 	newSlice := reflect.New(slice.Type()).Elem()
+	condition, isCondition := compareValue.(Condition)
 
 	// Iterate through each element
 	for i := 0; i < slice.Len(); i++ {
@@ -190,17 +209,16 @@ func filterSliceByField(slice reflect.Value, fieldName string, compareValue inte
 			elem = elem.Elem()
 		}
 
-		var fieldValue reflect.Value
-		var found bool
-
-		// Handle nested fields vs direct fields
-		if strings.Contains(fieldName, ".") {
-			fieldValue, found = GetFieldValue(elem, fieldName)
-		} else {
-			fieldValue = elem.FieldByName(fieldName)
-			found = fieldValue.IsValid()
+		// Conditions built with Field, And, Or or Not evaluate the whole element
+		if isCondition {
+			if condition.matchValue(elem) {
+				newSlice = reflect.Append(newSlice, elem)
+			}
+			continue
 		}
 
+		fieldValue, found := lookupField(elem, fieldName)
+
 		// Skip if field not found
 		if !found {
 			continue
@@ -215,30 +233,78 @@ func filterSliceByField(slice reflect.Value, fieldName string, compareValue inte
 	return newSlice
 }
 
-// buildWhereExpression builds the DynamoDB expression for a where query
-func buildWhereExpression(typeName, fieldName string, fieldValue interface{}) (expression.Expression, error) {
+// buildFieldCondition builds the filter condition for a single field, named by the attribute fields maps it to
+// A single value is compared for equality unless it is a Comparison, and multiple values use IN
+// A Condition built with Field, And, Or or Not names its own fields, so fieldName is ignored
+func buildFieldCondition(fields *fieldMap, fieldName string, fieldValues []interface{}) (expression.ConditionBuilder, error) {
+	if len(fieldValues) == 0 {
+		return expression.ConditionBuilder{}, validationErrorf("no values given for %s", fieldName)
+	}
+	attribute := fields.attributePath(fieldName)
+	if isTimestamp(attribute) {
+		values := make([]interface{}, len(fieldValues))
+		for i, v := range fieldValues {
+			values[i] = timestampValue(v)
+		}
+		fieldValues = values
+	}
+	if len(fieldValues) == 1 {
+		if comparison, ok := fieldValues[0].(Comparison); ok {
+			return comparison.condition(attribute)
+		}
+		if condition, ok := fieldValues[0].(Condition); ok {
+			return condition.condition(fields)
+		}
+		// Single value - use equality
+		return equalCondition(expression.Name(attribute), fieldValues[0]), nil
+	}
+
+	// Multiple values - use IN operator
+	values := make([]expression.OperandBuilder, len(fieldValues))
+	empty := false
+	for j, val := range fieldValues {
+		values[j] = expression.Value(val)
+		empty = empty || isEmptyKey(reflect.ValueOf(val))
+	}
+	condition := expression.Name(attribute).In(values[0], values[1:]...)
+	if empty {
+		condition = expression.Name(attribute).AttributeNotExists().Or(condition)
+	}
+	return condition, nil
+}
+
+// buildWhereExpression builds the DynamoDB expression for a where query, loading only the projection if one is given
+// fields maps the field names to attributes, and may be nil when they are the same
+func buildWhereExpression(fields *fieldMap, typeName, fieldName string, fieldValue interface{}, projection ...string) (expression.Expression, error) {
 	// Create key condition for the Type
 	keyCondition := expression.Key("Type").Equal(expression.Value(typeName))
 
 	// Create filter condition for the field
-	fieldCondition := expression.Name(fieldName).Equal(expression.Value(fieldValue))
+	fieldCondition, err := buildFieldCondition(fields, fieldName, []interface{}{fieldValue})
+	if err != nil {
+		return expression.Expression{}, err
+	}
 
 	// Add soft delete conditions
 	softDeleteCond := expression.Not(expression.Name("DeletedAt").AttributeExists())
 	softDeleteCond2 := expression.Not(expression.Name("DeletedAt").NotEqual(expression.Value(nil)))
 
 	// Build the complete expression
-	return expression.NewBuilder().
+	builder := expression.NewBuilder().
 		WithKeyCondition(keyCondition).
-		WithFilter(fieldCondition.And(softDeleteCond.Or(softDeleteCond2))).
-		Build()
+		WithFilter(fieldCondition.And(softDeleteCond.Or(softDeleteCond2)))
+	return withProjection(builder, fields, projection).Build()
 }
 
 // buildWhereV4Expression builds a comprehensive DynamoDB expression for multiple where conditions
 func buildWhereV4Expression(typeName string, conditions []WhereV4Condition) (expression.Expression, error) {
 	// Create key condition for the Type
-	keyCondition := expression.Key("Type").Equal(expression.Value(typeName))
+	return buildWhereV4KeyExpression(nil, expression.Key("Type").Equal(expression.Value(typeName)), conditions)
+}
 
+// buildWhereV4KeyExpression builds the expression for multiple where conditions on top of a key condition,
+// loading only the projection if one is given. fields maps the field names to attributes
+func buildWhereV4KeyExpression(fields *fieldMap, keyCondition expression.KeyConditionBuilder, conditions []WhereV4Condition, projection ...string) (expression.Expression, error) {
 	// Add soft delete conditions
 	softDeleteCond := expression.Not(expression.Name("DeletedAt").AttributeExists())
 	softDeleteCond2 := expression.Not(expression.Name("DeletedAt").NotEqual(expression.Value(nil)))
@@ -251,18 +317,9 @@ func buildWhereV4Expression(typeName string, conditions []WhereV4Condition) (exp
 		var fieldFilterCondition expression.ConditionBuilder
 
 		for i, condition := range conditions {
-			var conditionExpr expression.ConditionBuilder
-
-			if len(condition.FieldValues) == 1 {
-				// Single value - use equality
-				conditionExpr = expression.Name(condition.FieldName).Equal(expression.Value(condition.FieldValues[0]))
-			} else {
-				// Multiple values - use IN operator
-				values := make([]expression.OperandBuilder, len(condition.FieldValues))
-				for j, val := range condition.FieldValues {
-					values[j] = expression.Value(val)
-				}
-				conditionExpr = expression.Name(condition.FieldName).In(values[0], values[1:]...)
+			conditionExpr, err := buildFieldCondition(fields, condition.FieldName, condition.FieldValues)
+			if err != nil {
+				return expression.Expression{}, err
 			}
 
 			if i == 0 {
@@ -277,30 +334,31 @@ func buildWhereV4Expression(typeName string, conditions []WhereV4Condition) (exp
 	}
 
 	// Build the complete expression
-	return expression.NewBuilder().
+	builder := expression.NewBuilder().
 		WithKeyCondition(keyCondition).
-		WithFilter(finalFilter).
-		Build()
+		WithFilter(finalFilter)
+	return withProjection(builder, fields, projection).Build()
 }
 
-// executeWhereQuery executes a DynamoDB query with the given expression
+// executeWhereQuery executes a DynamoDB query with the given expression, following every page of results
 func (o *Operator) executeWhereQuery(expr expression.Expression, result interface{}) *Operator {
-	response, err := svc.Query(context.TODO(), &dynamodb.QueryInput{
-		TableName:                 aws.String(dynamoDBTableName),
+	return o.executeIndexQuery(expr, nil, result)
+}
+
+// executeIndexQuery executes a DynamoDB query against the named index, or the base table when index is nil
+func (o *Operator) executeIndexQuery(expr expression.Expression, index *string, result interface{}) *Operator {
+	err := o.queryInto(&dynamodb.QueryInput{
+		TableName:                 aws.String(o.tableName),
+		IndexName:                 index,
 		ExpressionAttributeNames:  expr.Names(),
 		ExpressionAttributeValues: expr.Values(),
 		KeyConditionExpression:    expr.KeyCondition(),
 		FilterExpression:          expr.Filter(),
-	})
-
-	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during Where operation: %v", err)
-		return o
-	}
+		ProjectionExpression:      expr.Projection(),
+	}, result)
 
-	err = attributevalue.UnmarshalListOfMaps(response.Items, result)
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during Where operation: %v", err)
+		o.Err = newOperationError("Where", result, err)
 	}
 
 	return o
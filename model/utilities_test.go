@@ -393,7 +393,7 @@ func TestFilterSliceByField(t *testing.T) {
 }
 
 func TestBuildWhereExpression(t *testing.T) {
-	expr, err := buildWhereExpression("test_user", "Name", "John")
+	expr, err := buildWhereExpression(nil, "test_user", "Name", "John")
 	require.NoError(t, err)
 
 	// Check expression pieces are non-nil
@@ -428,8 +428,6 @@ func TestBuildWhereExpression(t *testing.T) {
 
 func TestExecuteWhereQuery_Error(t *testing.T) {
 	mockSvc := mocks.NewDynamoDBAPI(t)
-	svc = mockSvc
-	dynamoDBTableName = "test-table"
 
 	expr := expression.Expression{
 		// mock empty expression parts
@@ -438,7 +436,7 @@ func TestExecuteWhereQuery_Error(t *testing.T) {
 	mockSvc.On("Query", mock.Anything, mock.Anything, mock.Anything).
 		Return(nil, errors.New("query failed"))
 
-	op := &Operator{}
+	op := NewMagicModelOperatorWithClient(mockSvc, "test-table")
 	op.executeWhereQuery(expr, &[]TestUser{})
 
 	assert.Error(t, op.Err)
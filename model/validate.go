@@ -0,0 +1,279 @@
+package model
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldError is a single field that broke one of its validation rules
+type FieldError struct {
+	// Field is the path of the field, with nested fields separated by dots, e.g. "Home.Address.City"
+	Field string
+	// Rule is the rule that failed: required, min, max, oneof or email
+	Rule    string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// ValidationError lists every field of a model that broke the rules declared in its mm tags,
+// e.g. `mm:"required,min=1,max=64"`. It matches ErrValidation with errors.Is
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	reasons := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		reasons[i] = field.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrValidation, strings.Join(reasons, "; "))
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// fieldRules are the validation rules declared in the mm tag of a single field
+// min and max bound the value of numbers and the length of strings, slices and maps
+type fieldRules struct {
+	required bool
+	min      *float64
+	max      *float64
+	oneOf    []string
+	email    bool
+}
+
+// newFieldRules parses the validation rules among options, returning nil when the field has none
+func newFieldRules(t reflect.Type, field reflect.StructField, options map[string]string) (*fieldRules, error) {
+	rules := &fieldRules{}
+	found := false
+
+	if _, ok := options["required"]; ok {
+		rules.required, found = true, true
+	}
+	for _, bound := range []struct {
+		name   string
+		target **float64
+	}{{"min", &rules.min}, {"max", &rules.max}} {
+		value, ok := options[bound.name]
+		if !ok {
+			continue
+		}
+		if _, ok := measure(reflect.Zero(field.Type)); !ok {
+			return nil, validationErrorf("%s on %s.%s needs a number, string, slice or map, got %s", bound.name, t.Name(), field.Name, field.Type)
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, validationErrorf("%s on %s.%s must be a number, got %q", bound.name, t.Name(), field.Name, value)
+		}
+		*bound.target, found = &n, true
+	}
+	if value, ok := options["oneof"]; ok {
+		if value == "" {
+			return nil, validationErrorf("oneof on %s.%s must list the allowed values, e.g. oneof=a|b", t.Name(), field.Name)
+		}
+		rules.oneOf, found = strings.Split(value, "|"), true
+	}
+	if _, ok := options["email"]; ok {
+		if indirectType(field.Type).Kind() != reflect.String {
+			return nil, validationErrorf("email on %s.%s needs a string, got %s", t.Name(), field.Name, field.Type)
+		}
+		rules.email, found = true, true
+	}
+
+	if !found {
+		return nil, nil
+	}
+	return rules, nil
+}
+
+// check appends an error for every rule v breaks. Only required applies to nil pointers,
+// so optional fields should be pointers
+func (r *fieldRules) check(path string, v reflect.Value, errs []FieldError) []FieldError {
+	if r.required && v.IsZero() {
+		return append(errs, FieldError{Field: path, Rule: "required", Message: "is required"})
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return errs
+		}
+		v = v.Elem()
+	}
+
+	if size, ok := measure(v); ok {
+		unit := ""
+		switch v.Kind() {
+		case reflect.String:
+			unit = " characters"
+		case reflect.Slice, reflect.Array, reflect.Map:
+			unit = " items"
+		}
+		if r.min != nil && size < *r.min {
+			errs = append(errs, FieldError{Field: path, Rule: "min", Message: fmt.Sprintf("must be at least %s%s", formatBound(*r.min), unit)})
+		}
+		if r.max != nil && size > *r.max {
+			errs = append(errs, FieldError{Field: path, Rule: "max", Message: fmt.Sprintf("must be at most %s%s", formatBound(*r.max), unit)})
+		}
+	}
+
+	if r.oneOf != nil {
+		value := fmt.Sprint(v.Interface())
+		allowed := false
+		for _, option := range r.oneOf {
+			allowed = allowed || option == value
+		}
+		if !allowed {
+			errs = append(errs, FieldError{Field: path, Rule: "oneof", Message: "must be one of " + strings.Join(r.oneOf, ", ")})
+		}
+	}
+
+	if r.email {
+		address, err := mail.ParseAddress(v.String())
+		if err != nil || address.Address != v.String() {
+			errs = append(errs, FieldError{Field: path, Rule: "email", Message: "must be an email address"})
+		}
+	}
+	return errs
+}
+
+// measure returns what min and max compare: the value of a number or the length of a string,
+// slice or map. Strings are measured in characters
+func measure(v reflect.Value) (float64, bool) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v = reflect.Zero(v.Type().Elem())
+			continue
+		}
+		v = v.Elem()
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), true
+	case v.Kind() == reflect.Slice, v.Kind() == reflect.Array, v.Kind() == reflect.Map:
+		return float64(v.Len()), true
+	case v.CanInt():
+		return float64(v.Int()), true
+	case v.CanUint():
+		return float64(v.Uint()), true
+	case v.CanFloat():
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+func formatBound(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// validateModel checks every field of the model payload holds against its rules, including the
+// fields of nested structs. It returns a *ValidationError listing every failure, or nil
+func validateModel(payload reflect.Value) error {
+	errs, err := validateStruct(payload, "", nil)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Fields: errs}
+	}
+	return nil
+}
+
+// validateField checks a single field of the model payload holds, as Update only writes that field
+func validateField(payload reflect.Value, name string) error {
+	meta, err := metaFor(payload.Type())
+	if err != nil {
+		return err
+	}
+	field := payload.FieldByName(name)
+	if !field.IsValid() {
+		return nil
+	}
+
+	var errs []FieldError
+	if rules, ok := meta.rules[name]; ok {
+		errs = rules.check(name, field, errs)
+	}
+	errs, err = validateNested(field, name, errs)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Fields: errs}
+	}
+	return nil
+}
+
+// validateStruct appends the rule violations of the exported fields of v, whose path is prefix
+func validateStruct(v reflect.Value, prefix string, errs []FieldError) ([]FieldError, error) {
+	meta, err := metaFor(v.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		// Fields of embedded structs are stored at the top level, so their path has no prefix of its own
+		path := prefix + field.Name
+		if field.Anonymous {
+			path = strings.TrimSuffix(prefix, ".")
+		}
+
+		if rules, ok := meta.rules[field.Name]; ok && !field.Anonymous {
+			errs = rules.check(path, v.Field(i), errs)
+		}
+		errs, err = validateNested(v.Field(i), path, errs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return errs, nil
+}
+
+// validateNested validates the structs v holds directly, through a pointer, or as slice elements
+func validateNested(v reflect.Value, path string, errs []FieldError) ([]FieldError, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return errs, nil
+		}
+		v = v.Elem()
+	}
+
+	prefix := path
+	if prefix != "" {
+		prefix += "."
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			return errs, nil
+		}
+		return validateStruct(v, prefix, errs)
+	case reflect.Slice, reflect.Array:
+		var err error
+		for i := 0; i < v.Len() && err == nil; i++ {
+			errs, err = validateNested(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+		return errs, err
+	}
+	return errs, nil
+}
@@ -0,0 +1,210 @@
+package model
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type TestAddress struct {
+	City string `mm:"required,max=10"`
+}
+
+type TestHome struct {
+	Address *TestAddress
+}
+
+// TestValidatedUser declares validation rules on its own and nested fields
+type TestValidatedUser struct {
+	Model
+	Name   string   `mm:"required,min=2,max=8"`
+	Email  string   `mm:"email"`
+	Status string   `mm:"oneof=active|archived"`
+	Age    int      `mm:"min=1,max=150"`
+	Nick   *string  `mm:"min=3"`
+	Tags   []string `mm:"max=2"`
+	Home   TestHome
+	Pets   []TestAddress
+}
+
+func validUser() *TestValidatedUser {
+	return &TestValidatedUser{
+		Name:   "Buddy",
+		Email:  "buddy@example.com",
+		Status: "active",
+		Age:    3,
+		Home:   TestHome{Address: &TestAddress{City: "Austin"}},
+	}
+}
+
+func TestValidateModel(t *testing.T) {
+	short := "ab"
+
+	tests := []struct {
+		name   string
+		modify func(*TestValidatedUser)
+		expect []FieldError
+	}{
+		{
+			name:   "valid",
+			modify: func(u *TestValidatedUser) {},
+		},
+		{
+			name:   "required",
+			modify: func(u *TestValidatedUser) { u.Name = "" },
+			expect: []FieldError{{Field: "Name", Rule: "required", Message: "is required"}},
+		},
+		{
+			name: "bounds",
+			modify: func(u *TestValidatedUser) {
+				u.Name = "B"
+				u.Age = 200
+				u.Tags = []string{"a", "b", "c"}
+				u.Nick = &short
+			},
+			expect: []FieldError{
+				{Field: "Name", Rule: "min", Message: "must be at least 2 characters"},
+				{Field: "Age", Rule: "max", Message: "must be at most 150"},
+				{Field: "Nick", Rule: "min", Message: "must be at least 3 characters"},
+				{Field: "Tags", Rule: "max", Message: "must be at most 2 items"},
+			},
+		},
+		{
+			name:   "min_counts_characters",
+			modify: func(u *TestValidatedUser) { u.Name = "éé" },
+		},
+		{
+			name: "oneof_and_email",
+			modify: func(u *TestValidatedUser) {
+				u.Status = "deleted"
+				u.Email = "Buddy <buddy@example.com>"
+			},
+			expect: []FieldError{
+				{Field: "Email", Rule: "email", Message: "must be an email address"},
+				{Field: "Status", Rule: "oneof", Message: "must be one of active, archived"},
+			},
+		},
+		{
+			name: "nested",
+			modify: func(u *TestValidatedUser) {
+				u.Home.Address.City = ""
+				u.Pets = []TestAddress{{City: "Houston"}, {City: "San Francisco"}}
+			},
+			expect: []FieldError{
+				{Field: "Home.Address.City", Rule: "required", Message: "is required"},
+				{Field: "Pets[1].City", Rule: "max", Message: "must be at most 10 characters"},
+			},
+		},
+		{
+			name:   "nil_nested_pointer",
+			modify: func(u *TestValidatedUser) { u.Home.Address = nil },
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			user := validUser()
+			tc.modify(user)
+
+			err := validateModel(reflect.ValueOf(user).Elem())
+			if tc.expect == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr))
+			require.Equal(t, tc.expect, validationErr.Fields)
+			require.ErrorIs(t, err, ErrValidation)
+		})
+	}
+}
+
+func TestMetaFor_InvalidValidationRules(t *testing.T) {
+	type BadMin struct {
+		Model
+		Name string `mm:"min=abc"`
+	}
+	type BadEmail struct {
+		Model
+		Age int `mm:"email"`
+	}
+	type BadBound struct {
+		Model
+		Active bool `mm:"max=1"`
+	}
+
+	for _, q := range []interface{}{BadMin{}, BadEmail{}, BadBound{}} {
+		_, err := metaFor(reflect.TypeOf(q))
+		require.ErrorIs(t, err, ErrValidation)
+	}
+}
+
+func TestOperator_EnforcesValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupMock func(*mocks.DynamoDBAPI)
+		operation func(*Operator) error
+		expectErr string
+	}{
+		{
+			name:      "create",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			operation: func(op *Operator) error {
+				user := validUser()
+				user.Name = ""
+				return op.Create(user).Err
+			},
+			expectErr: "encountered an error during Create operation: validation failed: Name is required",
+		},
+		{
+			name:      "save",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			operation: func(op *Operator) error {
+				user := validUser()
+				user.Status = "deleted"
+				return op.Save(user).Err
+			},
+			expectErr: "Status must be one of active, archived",
+		},
+		{
+			name:      "update",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {},
+			operation: func(op *Operator) error {
+				return op.Update(validUser(), "Age", 0).Err
+			},
+			expectErr: "Age must be at least 1",
+		},
+		{
+			name: "update_checks_only_the_field",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil)
+			},
+			operation: func(op *Operator) error {
+				user := validUser()
+				user.Name = ""
+				return op.Update(user, "Age", 4).Err
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			tc.setupMock(mockDB)
+
+			err := tc.operation(NewMagicModelOperatorWithClient(mockDB, "test-table"))
+			if tc.expectErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrValidation)
+			require.Contains(t, err.Error(), tc.expectErr)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"reflect"
+)
+
+// preparedWrite is a validated and marshalled write that is ready to send to DynamoDB
+type preparedWrite struct {
+	key  map[string]types.AttributeValue
+	item map[string]types.AttributeValue
+	expr expression.Expression
+	// conflict is the error a failed condition check means, nil when the write is unconditional
+	conflict error
+	// done applies changes to the model that only hold once the write succeeded
+	done func()
+	// after is the model's After hook for the operation, if it has one
+	after func(ctx context.Context) error
+	// snapshot brings the dirty tracking snapshot of the model up to date with the write
+	snapshot func(s *snapshots)
+}
+
+// succeeded applies the changes deferred until the write went through and runs the After hook
+func (w *preparedWrite) succeeded(ctx context.Context) error {
+	if w.done != nil {
+		w.done()
+	}
+	if w.after != nil {
+		return w.after(ctx)
+	}
+	return nil
+}
+
+// refresh updates the snapshots in s once the write succeeded. s is nil when dirty tracking is off
+func (w *preparedWrite) refresh(s *snapshots) {
+	if s != nil && w.snapshot != nil {
+		w.snapshot(s)
+	}
+}
+
+// versionField returns the version field of the model held by payload, if it has one,
+// along with the attribute it is stored as
+func versionField(payload reflect.Value) (reflect.Value, string, error) {
+	meta, err := metaFor(payload.Type())
+	if err != nil {
+		return reflect.Value{}, "", err
+	}
+	if meta.version == nil {
+		return reflect.Value{}, "", nil
+	}
+	return payload.FieldByIndex(meta.version), meta.versionAttribute, nil
+}
+
+// versionCondition checks that the stored version still matches the one the model was loaded with
+// Items written before the model was versioned have no version attribute and count as version 0
+func versionCondition(name string, current int64) expression.ConditionBuilder {
+	if current == 0 {
+		return expression.AttributeNotExists(expression.Name(name)).Or(expression.Name(name).Equal(expression.Value(current)))
+	}
+	return expression.Name(name).Equal(expression.Value(current))
+}
+
+// isConditionFailed reports whether err is DynamoDB rejecting a write because its condition did not hold
+func isConditionFailed(err error) bool {
+	var conditionFailed *types.ConditionalCheckFailedException
+	return errors.As(err, &conditionFailed)
+}
@@ -0,0 +1,156 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Ilios-LLC/magicmodel-go/mocks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVersionedUser is a model using optimistic locking
+type TestVersionedUser struct {
+	Model
+	Name    string
+	Version int64 `mm:"version"`
+}
+
+func TestOperator_OptimisticLocking(t *testing.T) {
+	conflict := &types.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")}
+
+	tests := []struct {
+		name            string
+		setupMock       func(*mocks.DynamoDBAPI)
+		version         int64
+		operation       func(*Operator, *TestVersionedUser) *Operator
+		expectConflict  bool
+		expectedVersion int64
+	}{
+		{
+			name: "create_starts_at_one",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("PutItem", mock.Anything, mock.Anything, mock.Anything).Return(&dynamodb.PutItemOutput{}, nil)
+			},
+			operation: func(op *Operator, user *TestVersionedUser) *Operator {
+				return op.Create(user)
+			},
+			expectedVersion: 1,
+		},
+		{
+			name: "save_checks_and_increments_version",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("PutItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+					stored, ok := in.Item["Version"].(*types.AttributeValueMemberN)
+					return ok && stored.Value == "4" && in.ConditionExpression != nil
+				}), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil)
+			},
+			version: 3,
+			operation: func(op *Operator, user *TestVersionedUser) *Operator {
+				return op.Save(user)
+			},
+			expectedVersion: 4,
+		},
+		{
+			name: "save_conflict",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("PutItem", mock.Anything, mock.Anything, mock.Anything).Return(nil, conflict)
+			},
+			version: 3,
+			operation: func(op *Operator, user *TestVersionedUser) *Operator {
+				return op.Save(user)
+			},
+			expectConflict:  true,
+			expectedVersion: 3,
+		},
+		{
+			name: "update_checks_and_increments_version",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+					// Name, UpdatedAt, the new version and the checked version
+					return in.ConditionExpression != nil && len(in.ExpressionAttributeValues) == 4
+				}), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil)
+			},
+			version: 1,
+			operation: func(op *Operator, user *TestVersionedUser) *Operator {
+				return op.Update(user, "Name", "Jane Doe")
+			},
+			expectedVersion: 2,
+		},
+		{
+			name: "update_conflict",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("UpdateItem", mock.Anything, mock.Anything, mock.Anything).Return(nil, conflict)
+			},
+			version: 1,
+			operation: func(op *Operator, user *TestVersionedUser) *Operator {
+				return op.Update(user, "Name", "Jane Doe")
+			},
+			expectConflict:  true,
+			expectedVersion: 1,
+		},
+		{
+			name: "transaction_conflict",
+			setupMock: func(dbMock *mocks.DynamoDBAPI) {
+				dbMock.On("TransactWriteItems", mock.Anything, mock.Anything, mock.Anything).Return(nil, &types.TransactionCanceledException{
+					CancellationReasons: []types.CancellationReason{{Code: aws.String("ConditionalCheckFailed")}},
+				})
+			},
+			version: 2,
+			operation: func(op *Operator, user *TestVersionedUser) *Operator {
+				return op.Transaction(func(tx *Tx) error {
+					tx.Save(user)
+					return nil
+				})
+			},
+			expectConflict:  true,
+			expectedVersion: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDB := mocks.NewDynamoDBAPI(t)
+			if tc.setupMock != nil {
+				tc.setupMock(mockDB)
+			}
+
+			op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+			user := &TestVersionedUser{Name: "John Doe", Version: tc.version}
+			if tc.version > 0 {
+				user.ID, user.Type = "1", "test_versioned_user"
+			}
+			result := tc.operation(op, user)
+
+			if tc.expectConflict {
+				require.Error(t, result.Err)
+				require.True(t, errors.Is(result.Err, ErrVersionConflict), result.Err.Error())
+			} else {
+				require.NoError(t, result.Err)
+			}
+			require.Equal(t, tc.expectedVersion, user.Version)
+		})
+	}
+}
+
+func TestOperator_SaveMany_RejectsVersionedModels(t *testing.T) {
+	op := NewMagicModelOperatorWithClient(mocks.NewDynamoDBAPI(t), "test-table")
+	result := op.SaveMany(&[]TestVersionedUser{{Name: "John Doe"}})
+	require.Error(t, result.Err)
+	require.Contains(t, result.Err.Error(), "versioned models cannot be saved in a batch")
+}
+
+func TestMetaFor_InvalidVersionField(t *testing.T) {
+	type BadVersion struct {
+		Model
+		Version string `mm:"version"`
+	}
+
+	op := NewMagicModelOperatorWithClient(mocks.NewDynamoDBAPI(t), "test-table")
+	result := op.Save(&BadVersion{})
+	require.Error(t, result.Err)
+	require.Contains(t, result.Err.Error(), "must be a signed integer")
+}
@@ -1,10 +1,7 @@
 package model
 
 import (
-	"context"
-	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 )
@@ -25,32 +22,49 @@ func (o *Operator) Where(q interface{}, k string, v interface{}) *Operator {
 		return o
 	}
 
+	meta, err := metaOf(q)
+	if err != nil {
+		o.Err = newOperationError("Where", q, err)
+		return o
+	}
+
 	cond := expression.Key("Type").Equal(expression.Value(name))
-	cond2 := expression.Name(k).Equal(expression.Value(v))
 	softDeleteCond := expression.Not(expression.Name("DeletedAt").AttributeExists())
 	sofDeleteCond2 := expression.Not(expression.Name("DeletedAt").NotEqual(expression.Value(nil)))
-	expr, err := expression.NewBuilder().WithKeyCondition(cond).WithFilter(cond2.And(softDeleteCond.Or(sofDeleteCond2))).Build()
+	filter := softDeleteCond.Or(sofDeleteCond2)
+
+	// Query the field's index when it has one, otherwise filter the whole Type partition
+	var indexName *string
+	if index, key := meta.indexFor(k, v); index != nil {
+		cond = cond.And(key)
+		indexName = aws.String(index.name)
+	} else {
+		fieldCond, err := buildFieldCondition(meta.fields, k, []interface{}{v})
+		if err != nil {
+			o.Err = newOperationError("Where", q, err)
+			return o
+		}
+		filter = fieldCond.And(filter)
+	}
+
+	expr, err := withProjection(expression.NewBuilder().WithKeyCondition(cond).WithFilter(filter), meta.fields, o.projection).Build()
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during Where operation: %v", err)
+		o.Err = newOperationError("Where", q, err)
 		return o
 	}
 
-	response, err := svc.Query(context.TODO(), &dynamodb.QueryInput{
-		TableName:                 aws.String(dynamoDBTableName),
+	err = o.queryInto(&dynamodb.QueryInput{
+		TableName:                 aws.String(o.tableName),
+		IndexName:                 indexName,
 		ExpressionAttributeNames:  expr.Names(),
 		ExpressionAttributeValues: expr.Values(),
 		KeyConditionExpression:    expr.KeyCondition(),
 		FilterExpression:          expr.Filter(),
-	})
-
-	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during Where operation: %v", err)
-		return o
-	}
+		ProjectionExpression:      expr.Projection(),
+	}, q)
 
-	err = attributevalue.UnmarshalListOfMaps(response.Items, q)
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during Where operation: %v", err)
+		o.Err = newOperationError("Where", q, err)
 		return o
 	}
 
@@ -1,7 +1,6 @@
 package model
 
 import (
-	"fmt"
 	"reflect"
 )
 
@@ -35,7 +34,7 @@ func (o *Operator) WhereV2(isChain bool, q interface{}, fieldName string, fieldV
 
 	// Ensure we're working with a slice
 	if val.Kind() != reflect.Slice {
-		o.Err = fmt.Errorf("encountered an error during Where operation: %v", "q is not a slice")
+		o.Err = newOperationError("Where", q, validationErrorf("q is not a slice"))
 		return o
 	}
 
@@ -59,9 +58,14 @@ func (o *Operator) WhereV2(isChain bool, q interface{}, fieldName string, fieldV
 	}
 
 	// Build query expression
-	expr, err := buildWhereExpression(name, fieldName, fieldValue)
+	meta, err := metaOf(q)
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during Where operation: %v", err)
+		o.Err = newOperationError("Where", q, err)
+		return o
+	}
+	expr, err := buildWhereExpression(meta.fields, name, fieldName, fieldValue, o.projection...)
+	if err != nil {
+		o.Err = newOperationError("Where", q, err)
 		return o
 	}
 
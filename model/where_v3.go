@@ -1,7 +1,6 @@
 package model
 
 import (
-	"fmt"
 	"reflect"
 )
 
@@ -52,9 +51,14 @@ func (o *Operator) WhereV3(isChain bool, q interface{}, fieldName string, fieldV
 	}
 
 	// Build query expression
-	expr, err := buildWhereExpression(name, fieldName, fieldValue)
+	meta, err := metaOf(q)
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during Where operation: %v", err)
+		o.Err = newOperationError("Where", q, err)
+		return o
+	}
+	expr, err := buildWhereExpression(meta.fields, name, fieldName, fieldValue, o.projection...)
+	if err != nil {
+		o.Err = newOperationError("Where", q, err)
 		return o
 	}
 
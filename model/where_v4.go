@@ -1,13 +1,15 @@
 package model
 
 import (
-	"fmt"
 	"reflect"
 )
 
 // WhereV4 filters a collection based on a field name and value(s) with deferred execution
-// Supports both single values and arrays for OR conditions within the same field
+// Supports both single values and arrays for OR conditions within the same field,
+// as well as comparisons such as model.Gt(3) or model.BeginsWith("Lab")
+// A Condition built with model.Or, model.And or model.Not can be passed with an empty fieldName
 // Can be chained with other WhereV4 calls when isChain is true
+// Chains keep their conditions on the Operator, so prefer Operator.Query when it is shared between goroutines
 func (o *Operator) WhereV4(isChain bool, q interface{}, fieldName string, fieldValue interface{}) *Operator {
 	// Return early if there's already an error
 	if o.Err != nil {
@@ -60,7 +62,8 @@ func normalizeFieldValues(fieldValue interface{}) []interface{} {
 	val := reflect.ValueOf(fieldValue)
 
 	// If it's already a slice, convert to []interface{}
-	if val.Kind() == reflect.Slice {
+	// A []byte is a single binary value rather than a list of numbers
+	if val.Kind() == reflect.Slice && val.Type().Elem().Kind() != reflect.Uint8 {
 		result := make([]interface{}, val.Len())
 		for i := 0; i < val.Len(); i++ {
 			result[i] = val.Index(i).Interface()
@@ -75,17 +78,18 @@ func normalizeFieldValues(fieldValue interface{}) []interface{} {
 // executeWhereV4Query builds and executes a single DynamoDB query with all pending conditions
 func (o *Operator) executeWhereV4Query(typeName string, result interface{}) *Operator {
 	if len(o.PendingConditions) == 0 {
-		o.Err = fmt.Errorf("no conditions to execute in WhereV4")
+		o.Err = validationErrorf("no conditions to execute in WhereV4")
 		return o
 	}
 
 	// Build the comprehensive expression
-	expr, err := buildWhereV4Expression(typeName, o.PendingConditions)
+	// Equality on an indexed field queries that index instead of the whole Type partition
+	expr, index, err := planWhereV4(result, typeName, o.PendingConditions, o.projection...)
 	if err != nil {
-		o.Err = fmt.Errorf("encountered an error during WhereV4 operation: %v", err)
+		o.Err = newOperationError("WhereV4", result, err)
 		return o
 	}
 
 	// Execute the query using the existing helper
-	return o.executeWhereQuery(expr, result)
+	return o.executeIndexQuery(expr, index, result)
 }
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -231,6 +232,11 @@ func TestNormalizeFieldValues(t *testing.T) {
 			input:    []string{},
 			expected: []interface{}{},
 		},
+		{
+			name:     "byte_slice",
+			input:    []byte("ab"),
+			expected: []interface{}{[]byte("ab")},
+		},
 	}
 
 	for _, tc := range tests {
@@ -241,6 +247,32 @@ func TestNormalizeFieldValues(t *testing.T) {
 	}
 }
 
+// TestHashedDog is a model with a binary index key
+type TestHashedDog struct {
+	Model
+	Name string
+	Hash []byte `mm:"index=hash-index"`
+}
+
+func TestOperator_WhereV4_BinaryIndex(t *testing.T) {
+	hashQuery := mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		for _, value := range in.ExpressionAttributeValues {
+			if b, ok := value.(*types.AttributeValueMemberB); ok && string(b.Value) == "ab" {
+				return in.IndexName != nil && *in.IndexName == "hash-index"
+			}
+		}
+		return false
+	})
+
+	mockDB := mocks.NewDynamoDBAPI(t)
+	mockDB.On("Query", mock.Anything, hashQuery, mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Twice()
+
+	op := NewMagicModelOperatorWithClient(mockDB, "test-table")
+	var dogs []TestHashedDog
+	require.NoError(t, op.WhereV4(false, &dogs, "Hash", []byte("ab")).Err)
+	require.NoError(t, op.Query(&dogs).Where("Hash", []byte("ab")).Exec(context.Background()))
+}
+
 func TestBuildWhereV4Expression(t *testing.T) {
 	tests := []struct {
 		name       string